@@ -10,6 +10,11 @@ type Area struct {
 	Description string
 	Rooms       []*Room
 	Channel     chan Action
+	// how many ticks between resets, 0 turns them off
+	ResetInterval int
+	Resets        []Reset
+	// how many ticks between players regenerating
+	RegenInterval int
 }
 
 func (a Area) GetRoomByUUID(roomUUID string) (*Room, error) {
@@ -37,5 +42,5 @@ type AreaInfo struct {
 }
 
 func NewArea(uuid string, name string, description string) *Area {
-	return &Area{UUID: uuid, Name: name, Description: description}
+	return &Area{UUID: uuid, Name: name, Description: description, RegenInterval: 15}
 }
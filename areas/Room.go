@@ -2,9 +2,11 @@ package areas
 
 import (
 	"fmt"
+	"mud/combat"
 	"mud/items"
 	"mud/mobs"
 	"mud/players"
+	"sync"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -16,22 +18,66 @@ type Room struct {
 	Description string
 	Area        *AreaInfo
 	Exits       *ExitInfo
-	Items       []*items.Item
-	Players     []*players.Player
-	Mobs        []*mobs.Mob
+	// Items, Players and Mobs are looked at from other goroutines than the
+	// area's, ie tab completion and players walking in from another area, so
+	// once the room is loaded they are only used through the methods which
+	// take mu.
+	Items   []*items.Item
+	Players []*players.Player
+	Mobs    []*mobs.Mob
+	Combat  *combat.Combat
+	mu      sync.RWMutex
 }
 
-func (room Room) GetPlayerByName(playerName string) *players.Player {
-	playersInRoom := room.Players
-	for idx := range playersInRoom {
-		if playersInRoom[idx].Name == playerName {
-			return playersInRoom[idx]
+// GetItems returns what's lying in the room.  The slice is a copy, so it can
+// be looked through while the room changes.
+func (room *Room) GetItems() []*items.Item {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	return append([]*items.Item{}, room.Items...)
+}
+
+// GetPlayers returns who is in the room, as a copy.
+func (room *Room) GetPlayers() []*players.Player {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	return append([]*players.Player{}, room.Players...)
+}
+
+// GetMobs returns the mobs in the room, as a copy.
+func (room *Room) GetMobs() []*mobs.Mob {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	return append([]*mobs.Mob{}, room.Mobs...)
+}
+
+func (room *Room) AddMob(mob *mobs.Mob) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	room.Mobs = append(room.Mobs, mob)
+}
+
+func (room *Room) RemoveMob(mob *mobs.Mob) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	for idx := range room.Mobs {
+		if room.Mobs[idx] == mob {
+			room.Mobs = append(room.Mobs[:idx], room.Mobs[idx+1:]...)
+			return nil
 		}
 	}
-	return nil
+	return fmt.Errorf("mob not found")
 }
 
-func (room Room) AddPlayer(player *players.Player) {
+func (room *Room) AddPlayer(player *players.Player) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
 	playerIdx := -1
 	for idx := range room.Players {
 		if room.Players[idx].UUID == player.UUID {
@@ -47,7 +93,10 @@ func (room Room) AddPlayer(player *players.Player) {
 	}
 }
 
-func (room Room) RemovePlayer(player *players.Player) error {
+func (room *Room) RemovePlayer(player *players.Player) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
 	playersInRoom := room.Players
 	for idx, playerInRoom := range playersInRoom {
 		if playerInRoom.UUID == player.UUID {
@@ -69,7 +118,10 @@ func NewRoomWithAreaInfo(uuid string, area_uuid string, name string, description
 }
 
 func (room *Room) AddItem(db *sqlx.DB, item *items.Item) error {
+	room.mu.Lock()
 	room.Items = append(room.Items, item)
+	room.mu.Unlock()
+
 	err := item.SetLocation(db, "", room.UUID)
 	if err != nil {
 		return err
@@ -78,6 +130,9 @@ func (room *Room) AddItem(db *sqlx.DB, item *items.Item) error {
 }
 
 func (room *Room) RemoveItem(item *items.Item) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
 	items := room.Items
 	for itemIndex := range items {
 		if items[itemIndex].UUID == item.UUID {
@@ -1,21 +1,33 @@
 package areas
 
 import (
+	"context"
 	"fmt"
 	"mud/display"
+	"mud/notifications"
 	"mud/players"
+	"mud/sessions"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type Action struct {
-	Player    players.Player
+	Player    *players.Player
 	Command   string
 	Arguments []string
+	// how many ticks the player has to wait after this action before their
+	// next one runs.  Actions without lag run as soon as the player isn't
+	// waiting on another one, rather than on the beat.
+	Lag           int
+	UpdateChannel func(string)
+	// closed once the action has run, or been dropped, for whoever is waiting
+	// on it.  Actions without Done show the player their prompt instead.
+	Done chan struct{}
 }
 
-func (a *Action) GetPlayer() players.Player {
+func (a *Action) GetPlayer() *players.Player {
 	return a.Player
 }
 
@@ -28,82 +40,141 @@ func (a *Action) GetArguments() []string {
 }
 
 type ActionHandler interface {
-	Execute(db *sqlx.DB, player players.Player, action Action, updateChannel func(string))
+	Execute(db *sqlx.DB, player *players.Player, action Action, updateChannel func(string))
 }
 
-// TODO WTF is this?
-// type FooActionHandler struct{}
+var (
+	ActionHandlers   = map[string]ActionHandler{}
+	actionHandlersMu sync.RWMutex
+)
+
+// RegisterActionHandler lets the commands package hand off commands which
+// should run on the area's beat instead of straight away.
+func RegisterActionHandler(command string, handler ActionHandler) {
+	actionHandlersMu.Lock()
+	defer actionHandlersMu.Unlock()
 
-// func (h *FooActionHandler) Execute(db *sqlx.DB, player players.Player, action *Action, updateChannel func(string)) {
-// 	display.PrintWithColor(player, "FooActionHandler.Execute()\n", "danger")
-// }
+	ActionHandlers[command] = handler
+}
 
-// var ActionHandlers = map[string]ActionHandler{
-// 	"foo": &FooActionHandler{},
-// }
+func getActionHandler(command string) (ActionHandler, bool) {
+	actionHandlersMu.RLock()
+	defer actionHandlersMu.RUnlock()
 
-var ActionHandlers = map[string]ActionHandler{}
+	handler, ok := ActionHandlers[command]
+	return handler, ok
+}
 
 type PlayerActions struct {
 	Player  *players.Player
 	Actions []Action
+	// the tick at which the player is allowed to act again
+	ReadyAt int
 }
 
-func (a *Area) Run(db *sqlx.DB, ch chan Action, connections map[string]*players.Player) {
+// Run is the area's beat, it runs queued actions, combat, mobs, resets and
+// regen until the context is done.
+func (a *Area) Run(ctx context.Context, db *sqlx.DB, ch chan Action, connections *sessions.Registry, notifier *notifications.Notifier) {
 	ticker := time.NewTicker(time.Second)
 	tickerCounter := 0
 	defer ticker.Stop()
 
 	playerActionsMap := make(map[string]PlayerActions)
 
+	if err := a.LoadResets(db); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case action := <-ch:
 			player := action.GetPlayer()
+			if player == nil {
+				continue
+			}
 			pa := playerActionsMap[player.UUID]
+			pa.Player = player
 			pa.Actions = append(pa.Actions, action)
+			a.runActions(db, &pa, tickerCounter, false)
 			playerActionsMap[player.UUID] = pa
 		case <-ticker.C:
 			tickerCounter++
-			if tickerCounter%15 == 0 {
-				var playersInArea []*players.Player
-				playersInArea = make([]*players.Player, 0, len(connections))
-				for _, player := range connections {
-					areaUUID := a.UUID
-					playerArea := player.AreaUUID
-					if playerArea == areaUUID {
-						playersInArea = append(playersInArea, player)
-					}
-				}
+			if tickerCounter%combatRoundTicks == 0 {
+				a.processCombat(db, connections, notifier)
+			}
+			if tickerCounter%mobAITicks == 0 {
+				a.processMobs(db, connections, notifier)
+			}
+			if tickerCounter%decayTicks == 0 {
+				a.processDecay(db, notifier, time.Now())
+			}
+			if a.ResetInterval > 0 && tickerCounter%a.ResetInterval == 0 {
+				a.applyResets(db)
+			}
+			if a.RegenInterval > 0 && tickerCounter%a.RegenInterval == 0 {
+				playersInArea := connections.InArea(a.UUID)
 				for _, player := range playersInArea {
 					// Process what hapens on the beat.
 					display.PrintWithColor(player, "\nboom-boom\n", "danger")
 					if err := player.Regen(db); err != nil {
 						fmt.Printf("Error: %v\n", err)
 					}
-					display.PrintWithColor(player, fmt.Sprintf("\nHP: %d Mvt: %d> ", player.HP, player.Movement), "primary")
+					player.ShowPrompt()
 				}
 			}
 
-			// Process one action for each player
-			for _, playerActions := range playerActionsMap {
-				if len(playerActions.Actions) > 0 {
-					action := playerActions.Actions[0]
-					playerActions.Actions = playerActions.Actions[1:]
-
-					handler, ok := ActionHandlers[action.GetCommand()]
-					if !ok {
-						fmt.Println("Unknown action command: ", action.GetCommand())
-						continue
-
+			// Process the actions of each player who isn't still lagged
+			for playerUUID, playerActions := range playerActionsMap {
+				if !connections.Contains(playerUUID) {
+					for _, action := range playerActions.Actions {
+						action.finish()
 					}
-					handler.Execute(db, *playerActions.Player, action, func(message string) {
-						fmt.Println("Running command: ", action.GetCommand())
-					})
-				} else {
-					fmt.Println("No commands to run for player.")
+					delete(playerActionsMap, playerUUID)
+					continue
 				}
+				a.runActions(db, &playerActions, tickerCounter, true)
+				playerActionsMap[playerUUID] = playerActions
 			}
 		}
 	}
 }
+
+// runActions runs the player's actions until they are lagged, or out of
+// actions.  Actions with lag only run on the beat, the ones without can run as
+// soon as they come in.
+func (a *Area) runActions(db *sqlx.DB, playerActions *PlayerActions, tickerCounter int, onBeat bool) {
+	for len(playerActions.Actions) > 0 && tickerCounter >= playerActions.ReadyAt {
+		action := playerActions.Actions[0]
+		if action.Lag > 0 && !onBeat {
+			return
+		}
+		playerActions.Actions = playerActions.Actions[1:]
+		playerActions.ReadyAt = tickerCounter + action.Lag
+
+		handler, ok := getActionHandler(action.GetCommand())
+		if !ok {
+			fmt.Println("Unknown action command: ", action.GetCommand())
+			action.finish()
+			continue
+		}
+		updateChannel := action.UpdateChannel
+		if updateChannel == nil {
+			updateChannel = func(string) {}
+		}
+		handler.Execute(db, playerActions.Player, action, updateChannel)
+		a.publishTargets()
+		if action.Done == nil {
+			playerActions.Player.ShowPrompt()
+		}
+		action.finish()
+	}
+}
+
+// finish lets whoever is waiting on the action know it's over.
+func (a *Action) finish() {
+	if a.Done != nil {
+		close(a.Done)
+	}
+}
@@ -0,0 +1,55 @@
+package areas
+
+import (
+	"mud/players"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type recordingActionHandler struct {
+	ran []string
+}
+
+func (h *recordingActionHandler) Execute(db *sqlx.DB, player *players.Player, action Action, updateChannel func(string)) {
+	h.ran = append(h.ran, action.Command)
+}
+
+func TestActionsWithoutLagDontWaitForTheBeat(t *testing.T) {
+	handler := &recordingActionHandler{}
+	RegisterActionHandler("test look", handler)
+	RegisterActionHandler("test kill", handler)
+
+	area := NewArea("area", "Area", "")
+	look := Action{Command: "test look", Done: make(chan struct{})}
+	kill := Action{Command: "test kill", Lag: 2, Done: make(chan struct{})}
+	lookAgain := Action{Command: "test look", Done: make(chan struct{})}
+	playerActions := PlayerActions{Player: &players.Player{UUID: "bob"}, Actions: []Action{look, kill, lookAgain}}
+
+	steps := []struct {
+		tick     int
+		onBeat   bool
+		expected string
+	}{
+		// kill has to wait for the beat
+		{5, false, "test look"},
+		{5, true, "test look|test kill"},
+		// and then the player is lagged
+		{6, false, "test look|test kill"},
+		{7, false, "test look|test kill|test look"},
+	}
+	for _, step := range steps {
+		area.runActions(nil, &playerActions, step.tick, step.onBeat)
+		if got := strings.Join(handler.ran, "|"); got != step.expected {
+			t.Fatalf("at tick %d expected %q to have run, got %q", step.tick, step.expected, got)
+		}
+	}
+	for _, action := range []Action{look, kill, lookAgain} {
+		select {
+		case <-action.Done:
+		default:
+			t.Errorf("expected %s to be finished", action.Command)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package areas
+
+import (
+	"fmt"
+	"mud/combat"
+	"mud/items"
+	"mud/mobs"
+	"mud/notifications"
+	"mud/players"
+	"mud/sessions"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// how many ticks of the area clock make up one round of combat
+const combatRoundTicks = 3
+
+var (
+	worldRooms   players.Rooms
+	worldRoomsMu sync.RWMutex
+)
+
+// SetPlayerRooms registers what keeps track of which room each player is in,
+// so that dead players can be moved to the respawn room, which can be in
+// another area.
+func SetPlayerRooms(playerRooms players.Rooms) {
+	worldRoomsMu.Lock()
+	defer worldRoomsMu.Unlock()
+
+	worldRooms = playerRooms
+}
+
+func playerRooms() players.Rooms {
+	worldRoomsMu.RLock()
+	defer worldRoomsMu.RUnlock()
+
+	return worldRooms
+}
+
+// Target is who a player is fighting, as of the last time their area looked.
+type Target struct {
+	Name  string
+	HP    int32
+	MaxHP int32
+}
+
+type roomTarget struct {
+	roomUUID string
+	target   Target
+}
+
+var (
+	targets   = map[string]roomTarget{}
+	targetsMu sync.RWMutex
+)
+
+// TargetOf returns who the player is fighting.  Fights belong to the area's
+// goroutine, so everything else reads what the area last published instead.
+func TargetOf(playerUUID string) (Target, bool) {
+	targetsMu.RLock()
+	defer targetsMu.RUnlock()
+
+	published, ok := targets[playerUUID]
+	return published.target, ok
+}
+
+// publishTargets replaces the room's entries in targets with who the players
+// fighting in it are fighting now.  It's called whenever the fight changes, as
+// prompts are shown along with the messages about it.
+func (room *Room) publishTargets() {
+	fighting := map[string]Target{}
+	if room.Combat != nil {
+		for _, combatant := range append(append([]combat.Combatant{}, room.Combat.Aggressors...), room.Combat.Defenders...) {
+			player, ok := combatant.(*players.Player)
+			if !ok {
+				continue
+			}
+			switch target := room.Combat.TargetOf(player).(type) {
+			case *mobs.Mob:
+				fighting[player.UUID] = Target{Name: target.Name, HP: target.HP, MaxHP: target.MaxHP}
+			case *players.Player:
+				fighting[player.UUID] = Target{Name: target.Name, HP: target.HP, MaxHP: target.HPMax}
+			}
+		}
+	}
+
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+
+	for playerUUID, published := range targets {
+		if published.roomUUID == room.UUID {
+			delete(targets, playerUUID)
+		}
+	}
+	for playerUUID, target := range fighting {
+		targets[playerUUID] = roomTarget{roomUUID: room.UUID, target: target}
+	}
+}
+
+func (a *Area) publishTargets() {
+	for _, room := range a.Rooms {
+		room.publishTargets()
+	}
+}
+
+// Engage starts a fight between the player and the mob, or drags them both into
+// the fight that is already going on in the room.
+func (room *Room) Engage(player *players.Player, mob *mobs.Mob) {
+	if room.Combat == nil {
+		roomCombat := combat.NewCombat(nil, nil)
+		roomCombat.Engage(player, mob)
+		roomCombat.RollInitiative()
+		room.Combat = &roomCombat
+		return
+	}
+	room.Combat.Engage(player, mob)
+}
+
+func (a *Area) processCombat(db *sqlx.DB, connections *sessions.Registry, notifier *notifications.Notifier) {
+	for _, room := range a.Rooms {
+		if room.Combat == nil {
+			continue
+		}
+		room.runCombatRound(db, connections, notifier)
+		if room.Combat.IsOver() {
+			room.Combat = nil
+		}
+		room.publishTargets()
+	}
+}
+
+// Every combatant still standing gets one turn per round, in initiative order.
+func (room *Room) runCombatRound(db *sqlx.DB, connections *sessions.Registry, notifier *notifications.Notifier) {
+	// players can walk away from a fight, or leave the game altogether, in
+	// between rounds.
+	for _, combatant := range append([]combat.Combatant{}, room.Combat.TurnOrder...) {
+		if player, ok := combatant.(*players.Player); ok {
+			if _, roomUUID, connected := connections.Location(player.UUID); !connected || roomUUID != room.UUID {
+				room.Combat.Remove(player)
+			}
+		}
+	}
+
+	turnOrder := append([]combat.Combatant{}, room.Combat.TurnOrder...)
+	for _, combatant := range turnOrder {
+		if !room.Combat.Contains(combatant) || combatant.GetHP() <= 0 {
+			continue
+		}
+
+		target := room.Combat.TargetOf(combatant)
+		if target == nil {
+			continue
+		}
+
+		switch attacker := combatant.(type) {
+		case *players.Player:
+			mob, ok := target.(*mobs.Mob)
+			if !ok {
+				continue
+			}
+			room.playerAttacksMob(notifier, attacker, mob)
+		case *mobs.Mob:
+			player, ok := target.(*players.Player)
+			if !ok {
+				continue
+			}
+			room.mobAttacksPlayer(db, notifier, attacker, player)
+		}
+
+		if target.GetHP() <= 0 {
+			room.handleDeath(db, notifier, target)
+		}
+	}
+}
+
+func (room *Room) playerAttacksMob(notifier *notifications.Notifier, player *players.Player, mob *mobs.Mob) {
+	result := player.Attack(mob)
+	if !result.Hit {
+		notifier.NotifyPlayer(player.UUID, fmt.Sprintf("\nYou miss %s.\n", mob.Name))
+		notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s misses %s.\n", player.Name, mob.Name))
+		return
+	}
+
+	combat.ApplyAttackResult(result, mob)
+	room.publishTargets()
+	notifier.NotifyPlayer(player.UUID, fmt.Sprintf("\nYour %s %s %s for %s.\n", result.AttackName, hitVerb(result), mob.Name, result.DamageDescription()))
+	notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s's %s %s %s.\n", player.Name, result.AttackName, hitVerb(result), mob.Name))
+}
+
+func (room *Room) mobAttacksPlayer(db *sqlx.DB, notifier *notifications.Notifier, mob *mobs.Mob, player *players.Player) {
+	results := mob.ExecuteAction(player)
+	for _, result := range results {
+		if !result.Hit {
+			notifier.NotifyPlayer(player.UUID, fmt.Sprintf("\n%s's %s misses you.\n", mob.Name, result.AttackName))
+			notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s's %s misses %s.\n", mob.Name, result.AttackName, player.Name))
+			continue
+		}
+
+		combat.ApplyAttackResult(result, player)
+		room.publishTargets()
+		notifier.NotifyPlayer(player.UUID, fmt.Sprintf("\n%s's %s %s you for %s!\n", mob.Name, result.AttackName, hitVerb(result), result.DamageDescription()))
+		notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s's %s %s %s.\n", mob.Name, result.AttackName, hitVerb(result), player.Name))
+	}
+
+	if err := player.SetHP(db, player.HP); err != nil {
+		fmt.Printf("error updating player hp: %v\n", err)
+	}
+}
+
+func hitVerb(result *combat.AttackResult) string {
+	if result.Critical {
+		return "critically hits"
+	}
+	return "hits"
+}
+
+func (room *Room) handleDeath(db *sqlx.DB, notifier *notifications.Notifier, victim combat.Combatant) {
+	room.Combat.Remove(victim)
+	room.publishTargets()
+
+	switch victim := victim.(type) {
+	case *mobs.Mob:
+		if err := room.RemoveMob(victim); err != nil {
+			fmt.Printf("error removing mob %s from room %s: %v\n", victim.Name, room.UUID, err)
+		}
+		if err := mobs.DeleteMob(db, victim.ID); err != nil {
+			fmt.Printf("error deleting mob %s: %v\n", victim.Name, err)
+		}
+		notifier.NotifyRoom(room.UUID, "", fmt.Sprintf("\n%s is DEAD!!\n", victim.Name))
+
+		corpse, err := items.NewCorpse(db, victim.Name, room.UUID, nil)
+		if err != nil {
+			fmt.Printf("error creating corpse for %s: %v\n", victim.Name, err)
+			return
+		}
+		if err := room.AddItem(db, corpse); err != nil {
+			fmt.Printf("error leaving the corpse of %s: %v\n", victim.Name, err)
+		}
+	case *players.Player:
+		notifier.NotifyPlayer(victim.UUID, "\nYou have been KILLED!!\n")
+		notifier.NotifyRoom(room.UUID, victim.UUID, fmt.Sprintf("\n%s is DEAD!!\n", victim.Name))
+
+		corpse, err := victim.Die(db, playerRooms())
+		if corpse != nil {
+			if err := room.AddItem(db, corpse); err != nil {
+				fmt.Printf("error leaving the corpse of %s: %v\n", victim.Name, err)
+			}
+		}
+		if err != nil {
+			fmt.Printf("error handling death of %s: %v\n", victim.Name, err)
+			return
+		}
+		notifier.NotifyPlayer(victim.UUID, "\nYou wake up somewhere familiar, feeling weak.\n")
+		notifier.NotifyRoom(victim.RoomUUID, victim.UUID, fmt.Sprintf("\n%s appears, looking pale.\n", victim.Name))
+	}
+}
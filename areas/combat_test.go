@@ -0,0 +1,69 @@
+package areas
+
+import (
+	"mud/migrations"
+	"mud/mobs"
+	"mud/players"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestTargetOfFollowsTheFight(t *testing.T) {
+	room := &Room{UUID: "arena"}
+	player := &players.Player{UUID: "bob", Name: "Bob", HP: 10, HPMax: 10}
+	mob := &mobs.Mob{Name: "Goblin", HP: 4, MaxHP: 7}
+
+	room.Engage(player, mob)
+	room.publishTargets()
+	target, ok := TargetOf(player.UUID)
+	if !ok {
+		t.Fatal("expected Bob to be fighting")
+	}
+	if target != (Target{Name: "Goblin", HP: 4, MaxHP: 7}) {
+		t.Errorf("expected the goblin on 4/7, got %+v", target)
+	}
+
+	room.Combat = nil
+	room.publishTargets()
+	if target, ok := TargetOf(player.UUID); ok {
+		t.Errorf("expected Bob to have stopped fighting, got %+v", target)
+	}
+}
+
+func TestPromptShowsTheHealthOfAMobFromTheDatabase(t *testing.T) {
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	// every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+	db.MustExec("INSERT INTO mob_templates (slug, name, hp, actions) VALUES ('goblin', 'a goblin', 20, '[]')")
+
+	mob, err := mobs.NewMobFromTemplate(db, "goblin", "area", "arena")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mob.HP = 15
+
+	room := &Room{UUID: "arena"}
+	player := &players.Player{UUID: "bob", Name: "Bob", HP: 10, HPMax: 10, Prompt: "%c> "}
+	room.Engage(player, mob)
+	room.publishTargets()
+	t.Cleanup(func() {
+		room.Combat = nil
+		room.publishTargets()
+	})
+
+	players.SetPromptContext(func(player *players.Player) players.PromptContext {
+		target, ok := TargetOf(player.UUID)
+		return players.PromptContext{Fighting: ok, TargetHP: target.HP, TargetMaxHP: target.MaxHP}
+	})
+	t.Cleanup(func() { players.SetPromptContext(nil) })
+
+	if got := player.RenderPrompt(); got != "75%> " {
+		t.Errorf("expected the goblin on 15/20 to show as 75%%, got %q", got)
+	}
+}
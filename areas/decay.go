@@ -0,0 +1,46 @@
+package areas
+
+import (
+	"fmt"
+	"mud/items"
+	"mud/notifications"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// how many ticks of the area clock between checking for corpses to decay
+const decayTicks = 10
+
+// processDecay gets rid of the corpses which have been lying around long
+// enough.  Whatever was in them is left on the floor.
+func (a *Area) processDecay(db *sqlx.DB, notifier *notifications.Notifier, now time.Time) {
+	for _, room := range a.Rooms {
+		for _, item := range room.GetItems() {
+			if !item.HasDecayed(now) {
+				continue
+			}
+			room.decay(db, notifier, item)
+		}
+	}
+}
+
+func (room *Room) decay(db *sqlx.DB, notifier *notifications.Notifier, item *items.Item) {
+	for _, content := range item.Contents {
+		if err := room.AddItem(db, content); err != nil {
+			fmt.Printf("error dropping %s out of %s: %v\n", content.Name, item.Name, err)
+		}
+	}
+	item.Contents = nil
+
+	if err := room.RemoveItem(item); err != nil {
+		fmt.Printf("error removing %s from room %s: %v\n", item.Name, room.UUID, err)
+	}
+	if err := items.DeleteItem(db, item); err != nil {
+		fmt.Printf("error deleting %s: %v\n", item.Name, err)
+	}
+	if item.Name != "" {
+		notifier.NotifyRoom(room.UUID, "", fmt.Sprintf("\n%s%s crumbles to dust.\n", strings.ToUpper(item.Name[:1]), item.Name[1:]))
+	}
+}
@@ -0,0 +1,35 @@
+package areas
+
+// RoomInfo is the GMCP Room.Info package, which clients use to draw maps.
+type RoomInfo struct {
+	UUID     string            `json:"num"`
+	Name     string            `json:"name"`
+	AreaUUID string            `json:"area_uuid"`
+	Area     string            `json:"area"`
+	Exits    map[string]string `json:"exits"`
+}
+
+func (room *Room) Info() RoomInfo {
+	info := RoomInfo{UUID: room.UUID, Name: room.Name, AreaUUID: room.AreaUUID, Exits: map[string]string{}}
+	if room.Area != nil {
+		info.Area = room.Area.Name
+	}
+	if room.Exits == nil {
+		return info
+	}
+
+	exitMap := map[string]*Room{
+		"north": room.Exits.GetNorth(),
+		"south": room.Exits.GetSouth(),
+		"west":  room.Exits.GetWest(),
+		"east":  room.Exits.GetEast(),
+		"up":    room.Exits.GetUp(),
+		"down":  room.Exits.GetDown(),
+	}
+	for direction, exit := range exitMap {
+		if exit != nil && exit.UUID != "" {
+			info.Exits[direction] = exit.UUID
+		}
+	}
+	return info
+}
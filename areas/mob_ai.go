@@ -0,0 +1,128 @@
+package areas
+
+import (
+	"fmt"
+	"math/rand"
+	"mud/mobs"
+	"mud/notifications"
+	"mud/sessions"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// how many ticks of the area clock between mobs deciding what to do
+const mobAITicks = 5
+
+// the odds a wanderer sets off on any given turn, so they don't march in lockstep
+const wanderChance = 0.3
+
+// processMobs lets every mob in the loaded rooms of the area act on its
+// behaviors: wimpy mobs flee a losing fight, aggressive mobs pick fights with
+// players, and wanderers roam the area.
+func (a *Area) processMobs(db *sqlx.DB, connections *sessions.Registry, notifier *notifications.Notifier) {
+	for _, room := range a.Rooms {
+		for _, mob := range room.GetMobs() {
+			if mob.HP <= 0 {
+				continue
+			}
+
+			if room.Combat != nil && room.Combat.Contains(mob) {
+				if mob.WantsToFlee() && mob.CanMove() {
+					a.mobFlees(db, notifier, room, mob)
+				}
+				continue
+			}
+
+			if mob.HasBehavior(mobs.BehaviorAggressive) && a.mobAttacks(notifier, room, mob, connections) {
+				continue
+			}
+
+			if mob.HasBehavior(mobs.BehaviorWanderer) && mob.CanMove() && rand.Float64() < wanderChance {
+				if direction, destination := a.randomExit(room); destination != nil {
+					a.moveMob(db, notifier, room, destination, mob, direction)
+				}
+			}
+		}
+	}
+}
+
+// mobAttacks starts a fight with the first player the mob sees, and reports
+// whether it found anyone.
+func (a *Area) mobAttacks(notifier *notifications.Notifier, room *Room, mob *mobs.Mob, connections *sessions.Registry) bool {
+	for _, player := range connections.InRoom(room.UUID) {
+		if player.HP <= 0 {
+			continue
+		}
+		room.Engage(player, mob)
+		room.Combat.SetTarget(mob, player)
+		room.publishTargets()
+		notifier.NotifyPlayer(player.UUID, fmt.Sprintf("\n%s attacks you!\n", mob.Name))
+		notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s attacks %s!\n", mob.Name, player.Name))
+		return true
+	}
+	return false
+}
+
+func (a *Area) mobFlees(db *sqlx.DB, notifier *notifications.Notifier, room *Room, mob *mobs.Mob) {
+	direction, destination := a.randomExit(room)
+	if destination == nil {
+		return
+	}
+	room.Combat.Remove(mob)
+	if room.Combat.IsOver() {
+		room.Combat = nil
+	}
+	room.publishTargets()
+	notifier.NotifyRoom(room.UUID, "", fmt.Sprintf("\n%s flees from combat!\n", mob.Name))
+	a.moveMob(db, notifier, room, destination, mob, direction)
+}
+
+func (a *Area) moveMob(db *sqlx.DB, notifier *notifications.Notifier, from *Room, to *Room, mob *mobs.Mob, direction string) {
+	if err := mob.SetLocation(db, a.UUID, to.UUID); err != nil {
+		fmt.Printf("error moving mob: %v\n", err)
+		return
+	}
+	if err := from.RemoveMob(mob); err != nil {
+		fmt.Printf("error removing mob %s from room %s: %v\n", mob.Name, from.UUID, err)
+	}
+	to.AddMob(mob)
+
+	notifier.NotifyRoom(from.UUID, "", fmt.Sprintf("\n%s goes %s.\n", mob.Name, direction))
+	notifier.NotifyRoom(to.UUID, "", fmt.Sprintf("\n%s has arrived.\n", mob.Name))
+}
+
+// randomExit picks one of the room's exits which stays inside of this area,
+// mobs never wander off into somebody else's.
+func (a *Area) randomExit(room *Room) (string, *Room) {
+	if room.Exits == nil {
+		return "", nil
+	}
+	exitMap := map[string]*Room{
+		"north": room.Exits.GetNorth(),
+		"south": room.Exits.GetSouth(),
+		"west":  room.Exits.GetWest(),
+		"east":  room.Exits.GetEast(),
+		"up":    room.Exits.GetUp(),
+		"down":  room.Exits.GetDown(),
+	}
+
+	var directions []string
+	var destinations []*Room
+	for direction, exit := range exitMap {
+		if exit == nil || exit.UUID == "" {
+			continue
+		}
+		destination := a.loadedRoom(exit.UUID)
+		if destination == nil {
+			continue
+		}
+		directions = append(directions, direction)
+		destinations = append(destinations, destination)
+	}
+	if len(directions) == 0 {
+		return "", nil
+	}
+
+	idx := rand.Intn(len(directions))
+	return directions[idx], destinations[idx]
+}
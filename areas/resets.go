@@ -0,0 +1,134 @@
+package areas
+
+import (
+	"fmt"
+	"mud/items"
+	"mud/mobs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	ResetTypeMob  = "mob"
+	ResetTypeItem = "item"
+)
+
+// A Reset keeps up to Max copies of a mob (by slug) or an item (by template
+// uuid) in a room, so the area repopulates after players clear it out.  Mobs
+// which have wandered off still count, as long as they're in the area.
+type Reset struct {
+	Type     string
+	Target   string
+	RoomUUID string
+	Max      int
+	// mobs spawned by the reset get these, on top of their template's
+	Behaviors []string
+}
+
+func (a *Area) LoadResets(db *sqlx.DB) error {
+	err := db.QueryRow("SELECT COALESCE(reset_interval, 0) FROM areas WHERE uuid = ?", a.UUID).Scan(&a.ResetInterval)
+	if err != nil {
+		return fmt.Errorf("error retrieving reset interval for area %s: %v", a.UUID, err)
+	}
+
+	rows, err := db.Query("SELECT type, target, room_uuid, max, COALESCE(behaviors, '') FROM area_resets WHERE area_uuid = ?", a.UUID)
+	if err != nil {
+		return fmt.Errorf("error retrieving resets for area %s: %v", a.UUID, err)
+	}
+	defer rows.Close()
+
+	a.Resets = nil
+	for rows.Next() {
+		var reset Reset
+		var behaviors string
+		err := rows.Scan(&reset.Type, &reset.Target, &reset.RoomUUID, &reset.Max, &behaviors)
+		if err != nil {
+			return fmt.Errorf("error scanning resets for area %s: %v", a.UUID, err)
+		}
+		reset.Behaviors = mobs.ParseBehaviors(behaviors)
+		a.Resets = append(a.Resets, reset)
+	}
+	return rows.Err()
+}
+
+// applyResets tops every reset back up to its cap.  The database is the source
+// of truth for the counts, since rooms are only loaded into the area once a
+// player has wandered into them.
+func (a *Area) applyResets(db *sqlx.DB) {
+	// a wanderer can be anywhere in the area by the time the reset comes round,
+	// so a mob's resets are topped up to what they add up to across the area.
+	mobsWanted := map[string]int{}
+	for _, reset := range a.Resets {
+		if reset.Type == ResetTypeMob {
+			mobsWanted[reset.Target] += reset.Max
+		}
+	}
+	mobsMissing := map[string]int{}
+	for slug, wanted := range mobsWanted {
+		count, err := mobs.CountMobsInArea(db, slug, a.UUID)
+		if err != nil {
+			fmt.Printf("error applying reset: %v\n", err)
+			continue
+		}
+		mobsMissing[slug] = wanted - count
+	}
+
+	for _, reset := range a.Resets {
+		switch reset.Type {
+		case ResetTypeMob:
+			if mobsMissing[reset.Target] <= 0 {
+				continue
+			}
+			count, err := mobs.CountMobsInRoom(db, reset.Target, reset.RoomUUID)
+			if err != nil {
+				fmt.Printf("error applying reset: %v\n", err)
+				continue
+			}
+			for ; count < reset.Max && mobsMissing[reset.Target] > 0; count++ {
+				mob, err := mobs.NewMobFromTemplate(db, reset.Target, a.UUID, reset.RoomUUID)
+				if err != nil {
+					fmt.Printf("error applying reset: %v\n", err)
+					break
+				}
+				mobsMissing[reset.Target]--
+				if len(reset.Behaviors) > 0 {
+					behaviors := append(mob.GetBehaviors(), reset.Behaviors...)
+					if err := mob.SetBehaviors(db, behaviors); err != nil {
+						fmt.Printf("error applying reset: %v\n", err)
+					}
+				}
+				if room := a.loadedRoom(reset.RoomUUID); room != nil {
+					room.AddMob(mob)
+				}
+			}
+		case ResetTypeItem:
+			count, err := items.CountItemsFromTemplateInRoom(db, reset.Target, reset.RoomUUID)
+			if err != nil {
+				fmt.Printf("error applying reset: %v\n", err)
+				continue
+			}
+			for ; count < reset.Max; count++ {
+				item, err := items.SpawnItemInRoom(db, reset.Target, reset.RoomUUID)
+				if err != nil {
+					fmt.Printf("error applying reset: %v\n", err)
+					break
+				}
+				if room := a.loadedRoom(reset.RoomUUID); room != nil {
+					if err := room.AddItem(db, item); err != nil {
+						fmt.Printf("error applying reset: %v\n", err)
+					}
+				}
+			}
+		default:
+			fmt.Printf("unknown reset type %s in area %s\n", reset.Type, a.UUID)
+		}
+	}
+}
+
+func (a *Area) loadedRoom(roomUUID string) *Room {
+	room, err := a.GetRoomByUUID(roomUUID)
+	if err != nil {
+		return nil
+	}
+	return room
+}
@@ -0,0 +1,54 @@
+package areas
+
+import (
+	"mud/migrations"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestApplyResetsCountsWanderers(t *testing.T) {
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	// every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+	db.MustExec("INSERT INTO mob_templates (slug, name, hp, actions) VALUES ('aboleth', 'Aboleth', 10, '[]')")
+
+	area := NewArea("area", "Area", "")
+	area.Resets = []Reset{
+		{Type: ResetTypeMob, Target: "aboleth", RoomUUID: "floor", Max: 1},
+		{Type: ResetTypeMob, Target: "aboleth", RoomUUID: "stands", Max: 1},
+	}
+	countInArea := func() int {
+		var count int
+		if err := db.Get(&count, "SELECT COUNT(*) FROM mobs WHERE slug = 'aboleth' AND area_uuid = 'area'"); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	area.applyResets(db)
+	if count := countInArea(); count != 2 {
+		t.Fatalf("expected the resets to spawn 2 mobs, got %d", count)
+	}
+
+	// both wander off to somewhere else in the area, which doesn't make room
+	// for any more
+	db.MustExec("UPDATE mobs SET room_uuid = 'quarters'")
+	area.applyResets(db)
+	area.applyResets(db)
+	if count := countInArea(); count != 2 {
+		t.Errorf("expected wanderers to still count, got %d mobs", count)
+	}
+
+	// once one dies it's replaced, in whichever room is short
+	db.MustExec("DELETE FROM mobs WHERE id = (SELECT MIN(id) FROM mobs)")
+	area.applyResets(db)
+	if count := countInArea(); count != 2 {
+		t.Errorf("expected the dead mob to be replaced, got %d mobs", count)
+	}
+}
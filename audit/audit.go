@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// An Entry is one privileged command somebody ran, exactly as they typed it.
+type Entry struct {
+	ID          int64     `db:"id"`
+	AccountUUID string    `db:"account_uuid"`
+	PlayerUUID  string    `db:"player_uuid"`
+	PlayerName  string    `db:"player_name"`
+	Command     string    `db:"command"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func Record(db *sqlx.DB, accountUUID string, playerUUID string, playerName string, command string) error {
+	_, err := db.Exec("INSERT INTO admin_audit (account_uuid, player_uuid, player_name, command, created_at) VALUES (?, ?, ?, ?, ?)",
+		accountUUID, playerUUID, playerName, command, time.Now())
+	return err
+}
+
+// Recent returns the latest entries, newest first.  An empty playerName
+// returns everybody's.
+func Recent(db *sqlx.DB, playerName string, limit int) ([]Entry, error) {
+	var entries []Entry
+	query := `
+		SELECT id, COALESCE(account_uuid, '') AS account_uuid, player_uuid, player_name, command, created_at
+		FROM admin_audit
+		WHERE ? = '' OR LOWER(player_name) = LOWER(?)
+		ORDER BY id DESC
+		LIMIT ?
+	`
+	err := db.Select(&entries, query, playerName, playerName, limit)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mud/config"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Exit codes, so that scripts can tell a failure from a typo.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// A subcommand is one of the things the mud binary can do, ie `mud seed`.
+type subcommand struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+// subcommands is every subcommand, in the order `mud help` lists them.
+var subcommands []subcommand
+
+func init() {
+	subcommands = []subcommand{
+		{"serve", "serve [flags]", "run the server, what happens when no subcommand is given", runServe},
+		{"migrate", "migrate [-config file] up | down [steps] | status", "move the database schema up or down", runMigrate},
+		{"seed", "seed [-config file] [areas|items|classes|races|display|players]...", "fill the database with the world, all of it when nothing is named", runSeed},
+		{"validate", "validate [-config file]", "check the config and the world in the database, without starting the server", runValidate},
+		{"export", "export [-config file] [-dir directory]", "write every area in the database out as YAML", runExport},
+		{"import", "import [-config file] [-replace] file...", "read areas from YAML into the database", runImport},
+		{"player", "player [-config file] list | reset-password <name> | grant <account> [role]", "look after accounts and characters", runPlayer},
+		{"help", "help", "show this", runHelp},
+	}
+}
+
+// A usageError is a subcommand being given something it doesn't understand.
+type usageError struct {
+	usage string
+}
+
+func (e usageError) Error() string {
+	return "usage: mud " + e.usage
+}
+
+func usage(name string) error {
+	for _, sub := range subcommands {
+		if sub.name == name {
+			return usageError{usage: sub.usage}
+		}
+	}
+	return usageError{usage: name}
+}
+
+// run works out which subcommand is wanted and runs it, returning the exit
+// code.  Without one it serves, so `mud -telnet :4000` still works.
+func run(args []string) int {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	for _, sub := range subcommands {
+		if sub.name != name {
+			continue
+		}
+		err := sub.run(args)
+		var usageErr usageError
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, flag.ErrHelp):
+			return exitUsage
+		case errors.As(err, &usageErr):
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		default:
+			fmt.Fprintf(os.Stderr, "mud %s: %v\n", name, err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "mud: unknown command %q\n", name)
+	printHelp(os.Stderr)
+	return exitUsage
+}
+
+func runHelp(args []string) error {
+	printHelp(os.Stdout)
+	return nil
+}
+
+func printHelp(w io.Writer) {
+	fmt.Fprintln(w, "usage: mud <command> [arguments]")
+	fmt.Fprintln(w)
+	for _, sub := range subcommands {
+		fmt.Fprintf(w, "  %-10s %s\n", sub.name, sub.summary)
+		fmt.Fprintf(w, "  %-10s   mud %s\n", "", sub.usage)
+	}
+}
+
+// newFlagSet makes the flags for a subcommand, every one of them takes
+// -config.
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet("mud "+name, flag.ContinueOnError)
+	configPath := flags.String("config", "", "config file to read, $MUD_CONFIG or "+config.DefaultPath+" when empty")
+	return flags, configPath
+}
+
+func openDatabase(path string) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// openConfigured loads the config and opens the database it names, which is
+// where every subcommand but serve starts.
+func openConfigured(configPath string) (*config.Config, *sqlx.DB, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := openDatabase(cfg.Database.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, db, nil
+}
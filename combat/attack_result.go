@@ -0,0 +1,93 @@
+package combat
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DamageRoll struct {
+	Type   string
+	Amount int32
+	// magical damage gets past resistances to nonmagical attacks
+	Magical bool
+}
+
+// AttackResult describes the outcome of a single attack: whether it landed,
+// whether it was a critical hit, and how much of each type of damage it did.
+type AttackResult struct {
+	AttackerName string
+	AttackName   string
+	Hit          bool
+	Critical     bool
+	Damage       []DamageRoll
+}
+
+// DamageTaker is anything that can be on the receiving end of an attack.
+type DamageTaker interface {
+	GetName() string
+	IsImmuneTo(damage DamageRoll) bool
+	IsResistantTo(damage DamageRoll) bool
+	IsVulnerableTo(damage DamageRoll) bool
+	TakeDamage(amount int32)
+}
+
+func (r *AttackResult) TotalDamage() int32 {
+	var total int32
+	for idx := range r.Damage {
+		total += r.Damage[idx].Amount
+	}
+	return total
+}
+
+// DamageDescription lists the damage done by type, ie "7 piercing and 3 acid damage"
+func (r *AttackResult) DamageDescription() string {
+	var damageResults []string
+	for idx := range r.Damage {
+		if r.Damage[idx].Amount == 0 {
+			continue
+		}
+		if r.Damage[idx].Type == "" {
+			damageResults = append(damageResults, fmt.Sprintf("%d", r.Damage[idx].Amount))
+		} else {
+			damageResults = append(damageResults, fmt.Sprintf("%d %s", r.Damage[idx].Amount, r.Damage[idx].Type))
+		}
+	}
+
+	switch len(damageResults) {
+	case 0:
+		return "no damage"
+	case 1:
+		return damageResults[0] + " damage"
+	case 2:
+		return strings.Join(damageResults, " and ") + " damage"
+	default:
+		last := damageResults[len(damageResults)-1]
+		return strings.Join(damageResults[:len(damageResults)-1], ", ") + ", and " + last + " damage"
+	}
+}
+
+// ApplyAttackResult scales each type of damage in the result by the victim's
+// immunities, resistances and vulnerabilities, then takes the total off their
+// hit points.  The result is updated to reflect the damage actually done, and
+// the total is returned.
+func ApplyAttackResult(result *AttackResult, victim DamageTaker) int32 {
+	if !result.Hit {
+		return 0
+	}
+
+	for idx := range result.Damage {
+		damage := result.Damage[idx]
+		switch {
+		case victim.IsImmuneTo(damage):
+			result.Damage[idx].Amount = 0
+		case victim.IsResistantTo(damage):
+			result.Damage[idx].Amount /= 2
+		case victim.IsVulnerableTo(damage):
+			result.Damage[idx].Amount *= 2
+		}
+	}
+
+	total := result.TotalDamage()
+	victim.TakeDamage(total)
+	return total
+}
@@ -0,0 +1,69 @@
+package combat
+
+import (
+	"testing"
+)
+
+type TestDamageTaker struct {
+	hp                                  int32
+	immunities, resistances, weaknesses map[string]bool
+}
+
+func (d *TestDamageTaker) GetName() string {
+	return "Test Damage Taker"
+}
+
+func (d *TestDamageTaker) IsImmuneTo(damage DamageRoll) bool {
+	return d.immunities[damage.Type]
+}
+
+func (d *TestDamageTaker) IsResistantTo(damage DamageRoll) bool {
+	return d.resistances[damage.Type]
+}
+
+func (d *TestDamageTaker) IsVulnerableTo(damage DamageRoll) bool {
+	return d.weaknesses[damage.Type]
+}
+
+func (d *TestDamageTaker) TakeDamage(amount int32) {
+	d.hp -= amount
+}
+
+func TestApplyAttackResult(t *testing.T) {
+	victim := &TestDamageTaker{
+		hp:          100,
+		immunities:  map[string]bool{"poison": true},
+		resistances: map[string]bool{"slashing": true},
+		weaknesses:  map[string]bool{"fire": true},
+	}
+
+	result := &AttackResult{
+		Hit: true,
+		Damage: []DamageRoll{
+			{Type: "poison", Amount: 10},
+			{Type: "slashing", Amount: 7},
+			{Type: "fire", Amount: 4},
+			{Type: "cold", Amount: 5},
+		},
+	}
+
+	total := ApplyAttackResult(result, victim)
+	if total != 0+3+8+5 {
+		t.Errorf("expected 16 total damage, got %d", total)
+	}
+	if victim.hp != 84 {
+		t.Errorf("expected victim to have 84 hp left, got %d", victim.hp)
+	}
+	if description := result.DamageDescription(); description != "3 slashing, 8 fire, and 5 cold damage" {
+		t.Errorf("unexpected damage description: %s", description)
+	}
+}
+
+func TestApplyAttackResultMiss(t *testing.T) {
+	victim := &TestDamageTaker{hp: 100}
+	result := &AttackResult{Hit: false, Damage: []DamageRoll{{Type: "piercing", Amount: 10}}}
+
+	if total := ApplyAttackResult(result, victim); total != 0 || victim.hp != 100 {
+		t.Errorf("expected a miss to do no damage, got %d", total)
+	}
+}
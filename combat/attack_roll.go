@@ -2,18 +2,32 @@ package combat
 
 import (
 	"mud/utilities"
+	"strings"
 )
 
-func AttackRoll(combatant Combatant, opponent Combatant) bool {
+// AttackRoll rolls a d20 for the combatant against the opponent's armor class.
+// It returns whether the attack hit, and whether it was a critical hit.
+func AttackRoll(combatant Combatant, opponent Opponent) (bool, bool) {
 	abilities := combatant.GetAbilities()
 	attackModifier := abilities.GetAttackModifier("ranged")
 
 	d20Roll := utilities.DiceRoll("1d20")
 	if d20Roll == 1 {
-		return false
+		return false, false
 	} else if d20Roll == 20 {
-		return true
+		return true, true
 	}
 
-	return attackModifier+d20Roll >= opponent.GetArmorClass()
+	return attackModifier+d20Roll >= opponent.GetArmorClass(), false
+}
+
+// RollDamageDice rolls the damage dice for an attack.  A critical hit rolls all of
+// the attack's dice twice, but only adds the bonus once.
+func RollDamageDice(dice string, critical bool) int32 {
+	damage := utilities.DiceRoll(dice)
+	if critical {
+		diceWithoutBonus := strings.Split(dice, "+")[0]
+		damage += utilities.DiceRoll(diceWithoutBonus)
+	}
+	return damage
 }
@@ -10,6 +10,8 @@ type Combatant interface {
 	GetAbilities() Abilities
 	GetArmorClass() int32
 	RollInitiative() int32
+	GetName() string
+	GetHP() int32
 }
 
 type Opponent interface {
@@ -8,10 +8,11 @@ type Combat struct {
 	Aggressors []Combatant
 	Defenders  []Combatant
 	TurnOrder  []Combatant
+	Targets    map[Combatant]Combatant
 }
 
 func NewCombat(aggressors []Combatant, defenders []Combatant) Combat {
-	return Combat{Aggressors: aggressors, Defenders: defenders}
+	return Combat{Aggressors: aggressors, Defenders: defenders, Targets: make(map[Combatant]Combatant)}
 }
 
 func (c *Combat) AddAggressor(aggressor Combatant) {
@@ -61,3 +62,121 @@ func (c *Combat) RollInitiative() {
 		c.TurnOrder = append(c.TurnOrder, combatants[idx].Combatant)
 	}
 }
+
+// Engage puts the attacker and the defender on opposite sides of the combat,
+// adding whichever of them isn't already fighting, and points the attacker at
+// the defender.  The defender only picks the attacker as a target if it
+// doesn't already have one.
+func (c *Combat) Engage(attacker Combatant, defender Combatant) {
+	attackerIn := c.Contains(attacker)
+	defenderIn := c.Contains(defender)
+
+	switch {
+	case !attackerIn && !defenderIn:
+		c.AddAggressor(attacker)
+		c.AddDefender(defender)
+	case !attackerIn:
+		if c.IsAggressor(defender) {
+			c.AddDefender(attacker)
+		} else {
+			c.AddAggressor(attacker)
+		}
+	case !defenderIn:
+		if c.IsAggressor(attacker) {
+			c.AddDefender(defender)
+		} else {
+			c.AddAggressor(defender)
+		}
+	}
+
+	c.SetTarget(attacker, defender)
+	if c.TargetOf(defender) == nil {
+		c.SetTarget(defender, attacker)
+	}
+}
+
+func (c *Combat) Contains(combatant Combatant) bool {
+	return indexOf(c.Aggressors, combatant) != -1 || indexOf(c.Defenders, combatant) != -1
+}
+
+func (c *Combat) IsAggressor(combatant Combatant) bool {
+	return indexOf(c.Aggressors, combatant) != -1
+}
+
+// Remove takes the combatant out of the fight, whether they died or fled.
+// Anyone who was targeting them will pick a new target on their next turn.
+func (c *Combat) Remove(combatant Combatant) {
+	c.Aggressors = removeCombatant(c.Aggressors, combatant)
+	c.Defenders = removeCombatant(c.Defenders, combatant)
+	c.TurnOrder = removeCombatant(c.TurnOrder, combatant)
+
+	delete(c.Targets, combatant)
+	for attacker, target := range c.Targets {
+		if target == combatant {
+			delete(c.Targets, attacker)
+		}
+	}
+}
+
+func (c *Combat) SetTarget(attacker Combatant, target Combatant) {
+	if c.Targets == nil {
+		c.Targets = make(map[Combatant]Combatant)
+	}
+	c.Targets[attacker] = target
+}
+
+// TargetOf returns who the combatant is currently fighting.  If their chosen
+// target has left the fight, the first opponent still standing is returned
+// instead, or nil if there is nobody left to fight.
+func (c *Combat) TargetOf(combatant Combatant) Combatant {
+	if target, ok := c.Targets[combatant]; ok && c.Contains(target) && target.GetHP() > 0 {
+		return target
+	}
+
+	opponents := c.OpponentsOf(combatant)
+	if len(opponents) == 0 {
+		return nil
+	}
+	c.SetTarget(combatant, opponents[0])
+	return opponents[0]
+}
+
+// OpponentsOf returns the combatants on the other side of the fight who are
+// still standing.
+func (c *Combat) OpponentsOf(combatant Combatant) []Combatant {
+	var side []Combatant
+	if c.IsAggressor(combatant) {
+		side = c.Defenders
+	} else if indexOf(c.Defenders, combatant) != -1 {
+		side = c.Aggressors
+	}
+
+	var opponents []Combatant
+	for idx := range side {
+		if side[idx].GetHP() > 0 {
+			opponents = append(opponents, side[idx])
+		}
+	}
+	return opponents
+}
+
+func (c *Combat) IsOver() bool {
+	return len(c.Aggressors) == 0 || len(c.Defenders) == 0
+}
+
+func indexOf(combatants []Combatant, combatant Combatant) int {
+	for idx := range combatants {
+		if combatants[idx] == combatant {
+			return idx
+		}
+	}
+	return -1
+}
+
+func removeCombatant(combatants []Combatant, combatant Combatant) []Combatant {
+	idx := indexOf(combatants, combatant)
+	if idx == -1 {
+		return combatants
+	}
+	return append(combatants[:idx], combatants[idx+1:]...)
+}
@@ -7,38 +7,48 @@ import (
 type TestCombatant struct {
 	initiativeToReturn int32
 	armorClass         int32
+	name               string
+	hp                 int32
 }
 
-func (c TestCombatant) RollInitiative() int32 {
+func (c *TestCombatant) RollInitiative() int32 {
 	return c.initiativeToReturn
 }
 
-func (c TestCombatant) GetArmorClass() int32 {
+func (c *TestCombatant) GetArmorClass() int32 {
 	return c.armorClass
 }
 
-func (c TestCombatant) GetAbilities() Abilities {
+func (c *TestCombatant) GetAbilities() Abilities {
 	return nil
 }
 
+func (c *TestCombatant) GetName() string {
+	return c.name
+}
+
+func (c *TestCombatant) GetHP() int32 {
+	return c.hp
+}
+
 func TestRollInitiative(t *testing.T) {
 	testCases := []struct {
 		aggressors, defenders, expected []Combatant
 	}{
 		{
 			aggressors: []Combatant{
-				TestCombatant{initiativeToReturn: 10, armorClass: 10},
-				TestCombatant{initiativeToReturn: 19, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 10, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 19, armorClass: 10},
 			},
 			defenders: []Combatant{
-				TestCombatant{initiativeToReturn: 5, armorClass: 10},
-				TestCombatant{initiativeToReturn: 20, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 5, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 20, armorClass: 10},
 			},
 			expected: []Combatant{
-				TestCombatant{initiativeToReturn: 20, armorClass: 10},
-				TestCombatant{initiativeToReturn: 19, armorClass: 10},
-				TestCombatant{initiativeToReturn: 10, armorClass: 10},
-				TestCombatant{initiativeToReturn: 5, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 20, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 19, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 10, armorClass: 10},
+				&TestCombatant{initiativeToReturn: 5, armorClass: 10},
 			},
 		},
 	}
@@ -63,3 +73,65 @@ func TestRollInitiative(t *testing.T) {
 		}
 	}
 }
+
+func TestEngage(t *testing.T) {
+	player := &TestCombatant{name: "player", hp: 10}
+	ally := &TestCombatant{name: "ally", hp: 10}
+	mob := &TestCombatant{name: "mob", hp: 10}
+
+	combat := NewCombat(nil, nil)
+	combat.Engage(player, mob)
+	combat.RollInitiative()
+
+	if !combat.IsAggressor(player) || combat.IsAggressor(mob) {
+		t.Fatalf("expected player to be the aggressor and mob to be the defender")
+	}
+	if combat.TargetOf(player) != mob || combat.TargetOf(mob) != player {
+		t.Errorf("expected player and mob to target each other")
+	}
+
+	// joining a fight that's already underway puts you on the attacker's side
+	// and at the end of the turn order.
+	combat.Engage(ally, mob)
+	if !combat.IsAggressor(ally) {
+		t.Errorf("expected ally to join the aggressors")
+	}
+	if combat.TurnOrder[len(combat.TurnOrder)-1] != ally {
+		t.Errorf("expected ally to be last in the turn order")
+	}
+	if combat.TargetOf(mob) != player {
+		t.Errorf("expected mob to keep targeting player")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	player := &TestCombatant{name: "player", hp: 10}
+	ally := &TestCombatant{name: "ally", hp: 10}
+	mob := &TestCombatant{name: "mob", hp: 10}
+
+	combat := NewCombat(nil, nil)
+	combat.Engage(player, mob)
+	combat.RollInitiative()
+	combat.Engage(ally, mob)
+
+	combat.Remove(player)
+	if combat.Contains(player) {
+		t.Fatalf("expected player to be removed from combat")
+	}
+	if combat.TargetOf(mob) != ally {
+		t.Errorf("expected mob to switch targets to ally")
+	}
+	if combat.IsOver() {
+		t.Errorf("expected combat to continue while ally is fighting")
+	}
+
+	ally.hp = 0
+	if combat.TargetOf(mob) != nil {
+		t.Errorf("expected mob to have nobody left to fight")
+	}
+
+	combat.Remove(ally)
+	if !combat.IsOver() {
+		t.Errorf("expected combat to be over once one side is empty")
+	}
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// the actions admin commands queue on the area of the player they act on
+const (
+	teleportAction = "teleport"
+	forcedAction   = "forced"
+)
+
+// onlinePlayer finds a logged in player by name, and tells the admin when
+// there isn't one.
+func onlinePlayer(notifier *notifications.Notifier, admin *players.Player, name string) *players.Player {
+	target, ok := notifier.Registry.GetByName(name)
+	if !ok {
+		display.PrintWithColor(admin, fmt.Sprintf("%s isn't logged in.\n", name), "danger")
+		return nil
+	}
+	return target
+}
+
+// queueFor hands the action to the area the player is in, so that moving them
+// or running their commands happens on the same goroutine as everything else
+// they do.  When it's the admin's own action it waits for the area, so that
+// what it prints comes before their prompt.  Commands which were queued
+// themselves are already on an area's goroutine, which can't wait on an area,
+// so they hand it over in the background.
+func (r *CommandRouter) queueFor(notifier *notifications.Notifier, admin *players.Player, player *players.Player, command string, arguments []string, currentChannel chan areas.Action) error {
+	areaUUID, _, ok := notifier.Registry.Location(player.UUID)
+	if !ok {
+		return fmt.Errorf("%s isn't logged in", player.Name)
+	}
+	channel, ok := r.AreaChannels[areaUUID]
+	if !ok {
+		return fmt.Errorf("%s's area isn't running", player.Name)
+	}
+
+	action := areas.Action{Player: player, Command: command, Arguments: arguments}
+	if currentChannel == nil {
+		go func() {
+			select {
+			case channel <- action:
+			case <-r.AreasDone:
+			}
+		}()
+		return nil
+	}
+
+	if player.UUID == admin.UUID {
+		action.Done = make(chan struct{})
+	}
+	select {
+	case channel <- action:
+	case <-r.AreasDone:
+		return fmt.Errorf("the world has stopped")
+	}
+	if action.Done != nil {
+		select {
+		case <-action.Done:
+		case <-r.AreasDone:
+		}
+	}
+	return nil
+}
+
+// teleport queues moving the player straight to the room, wherever it is.
+func (r *CommandRouter) teleport(notifier *notifications.Notifier, worldState *world_state.WorldState, admin *players.Player, player *players.Player, roomUUID string, departure string, arrival string, currentChannel chan areas.Action) error {
+	if _, ok := worldState.RoomToAreaMap[roomUUID]; !ok {
+		return fmt.Errorf("there is no room %s", roomUUID)
+	}
+	return r.queueFor(notifier, admin, player, teleportAction, []string{roomUUID, departure, arrival}, currentChannel)
+}
+
+// TeleportActionHandler moves the player on their area's goroutine, for the
+// admin commands which move players around.  The arguments are the room, and
+// what the rooms they leave and arrive in are told.
+type TeleportActionHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+}
+
+func (h *TeleportActionHandler) Execute(db *sqlx.DB, player *players.Player, action areas.Action, updateChannel func(string)) {
+	arguments := action.GetArguments()
+	if len(arguments) != 3 {
+		fmt.Printf("teleporting %s needs a room, departure and arrival, got %q\n", player.Name, arguments)
+		return
+	}
+	if err := teleportPlayer(db, h.WorldState, h.Notifier, player, arguments[0], arguments[1], arguments[2]); err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("You couldn't be moved: %v\n", err), "danger")
+	}
+}
+
+// teleportPlayer moves the player straight to the room, wherever it is, and
+// shows them where they ended up.
+func teleportPlayer(db *sqlx.DB, worldState *world_state.WorldState, notifier *notifications.Notifier, player *players.Player, roomUUID string, departure string, arrival string) error {
+	if _, ok := worldState.RoomToAreaMap[roomUUID]; !ok {
+		return fmt.Errorf("there is no room %s", roomUUID)
+	}
+	// make sure the room is loaded before moving into it
+	worldState.GetRoom(roomUUID, false)
+
+	notifier.NotifyRoom(player.RoomUUID, player.UUID, departure)
+	worldState.RemovePlayerFromRoom(player.RoomUUID, player)
+	worldState.AddPlayerToRoom(roomUUID, player)
+	err := player.SetLocation(db, roomUUID)
+	if err != nil {
+		return err
+	}
+	notifier.NotifyRoom(roomUUID, player.UUID, arrival)
+
+	lookHandler := &LookCommandHandler{WorldState: worldState}
+	lookHandler.Execute(db, player, "look", []string{}, nil, func(string) {})
+	return nil
+}
+
+// ForcedActionHandler runs what the player was forced to do on their area's
+// goroutine.  The command line is the only argument.
+type ForcedActionHandler struct {
+	Router *CommandRouter
+}
+
+func (h *ForcedActionHandler) Execute(db *sqlx.DB, player *players.Player, action areas.Action, updateChannel func(string)) {
+	arguments := action.GetArguments()
+	if len(arguments) != 1 {
+		fmt.Printf("forcing %s needs a command, got %q\n", player.Name, arguments)
+		return
+	}
+	// without a channel queued commands run straight away, as this is their
+	// area's goroutine already
+	h.Router.HandleCommand(db, player, []byte(arguments[0]), nil, updateChannel)
+}
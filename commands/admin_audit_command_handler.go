@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/audit"
+	"mud/display"
+	"mud/players"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// how many audit entries /audit shows
+const auditEntriesShown = 20
+
+// AuditCommandHandler lists the latest admin commands, everyone's or just one
+// player's.
+type AuditCommandHandler struct{}
+
+func (h *AuditCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	playerName := ""
+	if len(arguments) > 0 {
+		playerName = arguments[0]
+	}
+
+	entries, err := audit.Recent(db, playerName, auditEntriesShown)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Error reading the audit log: %v\n", err), "danger")
+		return
+	}
+	if len(entries) == 0 {
+		display.PrintWithColor(player, "Nothing has been audited.\n", "reset")
+		return
+	}
+
+	for _, entry := range entries {
+		display.PrintWithColor(player, fmt.Sprintf("%s  ", entry.CreatedAt.Format("2006-01-02 15:04:05")), "secondary")
+		display.PrintWithColor(player, fmt.Sprintf("%-12s %s\n", entry.PlayerName, entry.Command), "reset")
+	}
+}
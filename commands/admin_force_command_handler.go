@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ForceCommandHandler makes another player run a command, as if they had typed
+// it themself.  They still need the role for it.  It's queued on the area the
+// player is in, so that it doesn't run alongside their own commands.
+type ForceCommandHandler struct {
+	Notifier *notifications.Notifier
+	Router   *CommandRouter
+}
+
+func (h *ForceCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	// the command is taken from what was typed, so that it keeps its case
+	parts := strings.SplitN(strings.Join(strings.Fields(command), " "), " ", 3)
+	if len(parts) < 3 {
+		display.PrintWithColor(player, "Usage: /force <player> <command>\n", "danger")
+		return
+	}
+
+	target := onlinePlayer(h.Notifier, player, parts[1])
+	if target == nil {
+		return
+	}
+	if target.UUID != player.UUID && target.HasRole(players.RoleAdmin) {
+		display.PrintWithColor(player, fmt.Sprintf("%s is an admin, and can't be forced.\n", target.Name), "danger")
+		return
+	}
+
+	display.PrintWithColor(player, fmt.Sprintf("You force %s to '%s'.\n", target.Name, parts[2]), "reset")
+	if err := h.Router.queueFor(h.Notifier, player, target, forcedAction, []string{parts[2]}, currentChannel); err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Couldn't force %s: %v\n", target.Name, err), "danger")
+	}
+}
+
+func (h *ForceCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *ForceCommandHandler) SetRouter(router *CommandRouter) {
+	h.Router = router
+}
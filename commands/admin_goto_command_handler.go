@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GotoCommandHandler takes the admin to a room, or to a player.  The move is
+// queued on the admin's area, like walking there would be.
+type GotoCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+	Router     *CommandRouter
+}
+
+func (h *GotoCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) != 1 {
+		display.PrintWithColor(player, "Usage: /goto <room uuid|player>\n", "danger")
+		return
+	}
+
+	roomUUID := arguments[0]
+	if target, ok := h.Notifier.Registry.GetByName(arguments[0]); ok {
+		_, roomUUID, _ = h.Notifier.Registry.Location(target.UUID)
+	}
+
+	err := h.Router.teleport(h.Notifier, h.WorldState, player, player, roomUUID,
+		fmt.Sprintf("\n%s vanishes in a puff of smoke.\n", player.Name),
+		fmt.Sprintf("\n%s appears in a puff of smoke.\n", player.Name), currentChannel)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("You can't go there: %v\n", err), "danger")
+	}
+}
+
+func (h *GotoCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *GotoCommandHandler) SetWorldState(worldState *world_state.WorldState) {
+	h.WorldState = worldState
+}
+
+func (h *GotoCommandHandler) SetRouter(router *CommandRouter) {
+	h.Router = router
+}
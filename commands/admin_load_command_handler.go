@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/items"
+	"mud/mobs"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LoadCommandHandler creates a mob from its template, or an item from its
+// template, in the admin's room.  It's queued on the area, since the room
+// belongs to the area's goroutine.
+type LoadCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+}
+
+func (h *LoadCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) < 2 {
+		display.PrintWithColor(player, "Usage: /load mob <slug> or /load item <template>\n", "danger")
+		return
+	}
+
+	room := h.WorldState.GetRoom(player.RoomUUID, false)
+	var name string
+	switch strings.ToLower(arguments[0]) {
+	case "mob":
+		mob, err := mobs.NewMobFromTemplate(db, strings.ToLower(arguments[1]), player.AreaUUID, room.UUID)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Couldn't load mob: %v\n", err), "danger")
+			return
+		}
+		room.Mobs = append(room.Mobs, mob)
+		name = mob.Name
+	case "item":
+		templateUUID, err := items.FindTemplateUUID(db, strings.Join(arguments[1:], " "))
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Couldn't load item: %v\n", err), "danger")
+			return
+		}
+		item, err := items.SpawnItemInRoom(db, templateUUID, room.UUID)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Couldn't load item: %v\n", err), "danger")
+			return
+		}
+		room.Items = append(room.Items, item)
+		name = item.Name
+	default:
+		display.PrintWithColor(player, "Usage: /load mob <slug> or /load item <template>\n", "danger")
+		return
+	}
+
+	display.PrintWithColor(player, fmt.Sprintf("You create %s.\n", name), "reset")
+	h.Notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s has created %s.\n", player.Name, name))
+}
+
+func (h *LoadCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *LoadCommandHandler) SetWorldState(worldState *world_state.WorldState) {
+	h.WorldState = worldState
+}
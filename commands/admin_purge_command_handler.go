@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/items"
+	"mud/mobs"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PurgeCommandHandler destroys every mob and item in the admin's room.  It's
+// queued on the area, since the room belongs to the area's goroutine.
+type PurgeCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+}
+
+func (h *PurgeCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) != 1 || !strings.EqualFold(arguments[0], "room") {
+		display.PrintWithColor(player, "Usage: /purge room\n", "danger")
+		return
+	}
+
+	room := h.WorldState.GetRoom(player.RoomUUID, false)
+	purgedMobs, purgedItems := 0, 0
+	for _, mob := range append([]*mobs.Mob{}, room.Mobs...) {
+		if err := mobs.DeleteMob(db, mob.ID); err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Error purging %s: %v\n", mob.Name, err), "danger")
+			continue
+		}
+		room.RemoveMob(mob)
+		purgedMobs++
+	}
+	// with the mobs gone there is nobody left to fight
+	room.Combat = nil
+
+	remainingItems := []*items.Item{}
+	for _, item := range room.Items {
+		if err := items.DeleteItem(db, item); err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Error purging %s: %v\n", item.Name, err), "danger")
+			remainingItems = append(remainingItems, item)
+			continue
+		}
+		purgedItems++
+	}
+	room.Items = remainingItems
+
+	display.PrintWithColor(player, fmt.Sprintf("You purge %d mobs and %d items from the room.\n", purgedMobs, purgedItems), "reset")
+	h.Notifier.NotifyRoom(room.UUID, player.UUID, fmt.Sprintf("\n%s waves their hand, and the room is swept clean.\n", player.Name))
+}
+
+func (h *PurgeCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *PurgeCommandHandler) SetWorldState(worldState *world_state.WorldState) {
+	h.WorldState = worldState
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RestoreCommandHandler heals a player, or the admin themself, completely.
+type RestoreCommandHandler struct {
+	Notifier *notifications.Notifier
+}
+
+func (h *RestoreCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	target := player
+	if len(arguments) > 0 {
+		target = onlinePlayer(h.Notifier, player, arguments[0])
+		if target == nil {
+			return
+		}
+	}
+
+	err := target.Restore(db)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Error restoring %s: %v\n", target.Name, err), "danger")
+		return
+	}
+
+	if target.UUID == player.UUID {
+		display.PrintWithColor(player, "You feel completely refreshed.\n", "reset")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("You restore %s.\n", target.Name), "reset")
+	h.Notifier.NotifyPlayer(target.UUID, fmt.Sprintf("\n%s has restored you, you feel completely refreshed.\n", player.Name))
+}
+
+func (h *RestoreCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
@@ -16,35 +16,41 @@ type AdminSetHealthCommandHandler struct {
 }
 
 func (h *AdminSetHealthCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) != 2 {
+		display.PrintWithColor(player, "Usage: /sethealth <player> <hp>\n", "danger")
+		return
+	}
 	target := arguments[0]
 	value := arguments[1]
 
-	retrievedPlayer, err := players.GetPlayerByName(db, target)
+	intValue, err := strconv.Atoi(value)
 	if err != nil {
-		display.PrintWithColor(player, fmt.Sprintf("Error retrieving player UUID: %v\n", err), "danger")
+		display.PrintWithColor(player, fmt.Sprintf("Error converting value to int: %v\n", err), "danger")
 		return
 	}
 
-	query := "UPDATE players SET health = ? WHERE UUID = ?"
-
-	_, err = db.Exec(query, value, retrievedPlayer.UUID)
+	// players who are logged in are updated in memory as well as the database
+	if playerInNotifier, ok := h.Notifier.Registry.GetByName(target); ok {
+		err = playerInNotifier.SetHP(db, int32(intValue))
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Error updating health: %v\n", err), "danger")
+			return
+		}
+		display.PrintWithColor(player, fmt.Sprintf("You set %s's health to %d\n", playerInNotifier.Name, intValue), "reset")
+		h.Notifier.NotifyPlayer(playerInNotifier.UUID, fmt.Sprintf("\n%s magically sets your health to %d\n", player.Name, intValue))
+		return
+	}
 
+	result, err := db.Exec("UPDATE players SET hp = ? WHERE LOWER(name) = LOWER(?)", intValue, target)
 	if err != nil {
 		display.PrintWithColor(player, fmt.Sprintf("Error updating health: %v\n", err), "danger")
 		return
 	}
-
-	intValue, err := strconv.Atoi(value)
-	if err != nil {
-		display.PrintWithColor(player, fmt.Sprintf("Error converting value to int: %v\n", err), "danger")
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		display.PrintWithColor(player, fmt.Sprintf("There is no player named %s\n", target), "danger")
 		return
 	}
-	playerInNotifier := h.Notifier.Players[retrievedPlayer.UUID]
-	// TODO what if the player isn't found
-	playerInNotifier.HP = int32(intValue)
 	display.PrintWithColor(player, fmt.Sprintf("You set %s's health to %d\n", target, intValue), "reset")
-	h.Notifier.NotifyPlayer(retrievedPlayer.UUID, fmt.Sprintf("\n%s magically sets your health to %d\n", player.Name, intValue))
-
 }
 
 func (h *AdminSetHealthCommandHandler) SetNotifier(notifier *notifications.Notifier) {
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	shutdownListeners   []func(reboot bool)
+	shutdownListenersMu sync.Mutex
+
+	// only one shutdown or reboot counts down at a time
+	pendingShutdown   *shutdownCountdown
+	pendingShutdownMu sync.Mutex
+)
+
+// OnShutdown registers what to do once a /shutdown or /reboot countdown has
+// run out.
+func OnShutdown(listener func(reboot bool)) {
+	shutdownListenersMu.Lock()
+	defer shutdownListenersMu.Unlock()
+
+	shutdownListeners = append(shutdownListeners, listener)
+}
+
+// ShutdownCommandHandler warns everyone, then shuts the server down, or
+// restarts it, after the given number of minutes.  "cancel" stops the
+// countdown.
+type ShutdownCommandHandler struct {
+	Reboot   bool
+	Notifier *notifications.Notifier
+}
+
+func (h *ShutdownCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) > 0 && strings.EqualFold(arguments[0], "cancel") {
+		if !cancelShutdown() {
+			display.PrintWithColor(player, "There is nothing to cancel.\n", "danger")
+			return
+		}
+		h.Notifier.NotifyAll(fmt.Sprintf("\n%s has called off the %s.\n", player.Name, h.verb()))
+		return
+	}
+
+	minutes := 0
+	if len(arguments) > 0 {
+		var err error
+		minutes, err = strconv.Atoi(arguments[0])
+		if err != nil || minutes < 0 {
+			display.PrintWithColor(player, fmt.Sprintf("Usage: /%s [minutes|cancel]\n", h.verb()), "danger")
+			return
+		}
+	}
+
+	countdown := &shutdownCountdown{reboot: h.Reboot, notifier: h.Notifier, cancel: make(chan struct{})}
+	pendingShutdownMu.Lock()
+	if pendingShutdown != nil {
+		pendingShutdownMu.Unlock()
+		display.PrintWithColor(player, "The server is already counting down, cancel it first.\n", "danger")
+		return
+	}
+	pendingShutdown = countdown
+	pendingShutdownMu.Unlock()
+
+	go countdown.run(time.Duration(minutes) * time.Minute)
+}
+
+func (h *ShutdownCommandHandler) verb() string {
+	if h.Reboot {
+		return "reboot"
+	}
+	return "shutdown"
+}
+
+func (h *ShutdownCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+type shutdownCountdown struct {
+	reboot   bool
+	notifier *notifications.Notifier
+	cancel   chan struct{}
+}
+
+func cancelShutdown() bool {
+	pendingShutdownMu.Lock()
+	defer pendingShutdownMu.Unlock()
+
+	if pendingShutdown == nil {
+		return false
+	}
+	close(pendingShutdown.cancel)
+	pendingShutdown = nil
+	return true
+}
+
+// countdownWarnings are the times left at which everyone is warned: every
+// minute, then at 30 and 10 seconds.
+func countdownWarnings(delay time.Duration) []time.Duration {
+	warnings := []time.Duration{}
+	for left := delay.Truncate(time.Minute); left >= time.Minute; left -= time.Minute {
+		warnings = append(warnings, left)
+	}
+	for _, left := range []time.Duration{30 * time.Second, 10 * time.Second} {
+		if left <= delay {
+			warnings = append(warnings, left)
+		}
+	}
+	return warnings
+}
+
+func (c *shutdownCountdown) run(delay time.Duration) {
+	what := "shut down"
+	if c.reboot {
+		what = "reboot"
+	}
+
+	deadline := time.Now().Add(delay)
+	for _, left := range countdownWarnings(delay) {
+		select {
+		case <-time.After(time.Until(deadline.Add(-left))):
+			c.notifier.NotifyAll(fmt.Sprintf("\nThe server will %s in %s.\n", what, left))
+		case <-c.cancel:
+			return
+		}
+	}
+	select {
+	case <-time.After(time.Until(deadline)):
+	case <-c.cancel:
+		return
+	}
+
+	pendingShutdownMu.Lock()
+	if pendingShutdown != c {
+		// cancelled just as it ran out
+		pendingShutdownMu.Unlock()
+		return
+	}
+	pendingShutdownMu.Unlock()
+
+	c.notifier.NotifyAll(fmt.Sprintf("\nThe server is going to %s now!\n", what))
+
+	shutdownListenersMu.Lock()
+	defer shutdownListenersMu.Unlock()
+	for _, listener := range shutdownListeners {
+		listener(c.reboot)
+	}
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SummonCommandHandler brings another player to the admin.  The move is
+// queued on the area the player is in.
+type SummonCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+	Router     *CommandRouter
+}
+
+func (h *SummonCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) != 1 {
+		display.PrintWithColor(player, "Usage: /summon <player>\n", "danger")
+		return
+	}
+
+	target := onlinePlayer(h.Notifier, player, arguments[0])
+	if target == nil {
+		return
+	}
+	if target.UUID == player.UUID {
+		display.PrintWithColor(player, "You're already here.\n", "reset")
+		return
+	}
+
+	_, roomUUID, _ := h.Notifier.Registry.Location(player.UUID)
+	display.PrintWithColor(target, fmt.Sprintf("\n%s has summoned you.\n", player.Name), "reset")
+	err := h.Router.teleport(h.Notifier, h.WorldState, player, target, roomUUID,
+		fmt.Sprintf("\n%s is whisked away.\n", target.Name),
+		fmt.Sprintf("\n%s arrives, summoned by %s.\n", target.Name, player.Name), currentChannel)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Couldn't summon %s: %v\n", target.Name, err), "danger")
+	}
+}
+
+func (h *SummonCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *SummonCommandHandler) SetWorldState(worldState *world_state.WorldState) {
+	h.WorldState = worldState
+}
+
+func (h *SummonCommandHandler) SetRouter(router *CommandRouter) {
+	h.Router = router
+}
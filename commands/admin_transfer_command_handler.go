@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TransferCommandHandler sends another player to a room.  The move is queued
+// on the area the player is in.
+type TransferCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+	Router     *CommandRouter
+}
+
+func (h *TransferCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) != 2 {
+		display.PrintWithColor(player, "Usage: /transfer <player> <room uuid>\n", "danger")
+		return
+	}
+
+	target := onlinePlayer(h.Notifier, player, arguments[0])
+	if target == nil {
+		return
+	}
+
+	display.PrintWithColor(target, fmt.Sprintf("\n%s sends you elsewhere.\n", player.Name), "reset")
+	err := h.Router.teleport(h.Notifier, h.WorldState, player, target, arguments[1],
+		fmt.Sprintf("\n%s is whisked away.\n", target.Name),
+		fmt.Sprintf("\n%s appears out of thin air.\n", target.Name), currentChannel)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Couldn't transfer %s: %v\n", target.Name, err), "danger")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("You transfer %s.\n", target.Name), "reset")
+}
+
+func (h *TransferCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *TransferCommandHandler) SetWorldState(worldState *world_state.WorldState) {
+	h.WorldState = worldState
+}
+
+func (h *TransferCommandHandler) SetRouter(router *CommandRouter) {
+	h.Router = router
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/players"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AliasCommandHandler saves commands under a shorter name.  $1 to $9 are
+// replaced with the alias's arguments and $* with all of them, and ; runs more
+// than one command, ie alias kl kill $1;look
+//
+//	alias [list]
+//	alias <name>
+//	alias <name> <commands>
+type AliasCommandHandler struct{}
+
+func (h *AliasCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) == 0 || (len(arguments) == 1 && strings.EqualFold(arguments[0], "list")) {
+		h.list(player)
+		return
+	}
+
+	name := strings.ToLower(arguments[0])
+	if len(arguments) == 1 {
+		expansion, ok := player.Aliases[name]
+		if !ok {
+			display.PrintWithColor(player, fmt.Sprintf("You don't have an alias called %s.\n", name), "reset")
+			return
+		}
+		display.PrintWithColor(player, fmt.Sprintf("%s: %s\n", name, expansion), "primary")
+		return
+	}
+
+	// what the alias runs is taken as typed, to keep its case and any ;
+	expansion := restOfLine(command, 2)
+	if len(arguments) == 2 && strings.HasPrefix(expansion, `"`) {
+		expansion = arguments[1]
+	}
+
+	err := checkAlias(player, name, expansion)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("%v.\n", err), "danger")
+		return
+	}
+
+	err = player.SetAlias(db, name, expansion)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Error saving your alias: %v\n", err), "danger")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("%s now runs %s\n", name, expansion), "reset")
+}
+
+// checkAlias makes sure the alias can be saved, and that it won't end up
+// calling itself.
+func checkAlias(player *players.Player, name string, expansion string) error {
+	if name == "alias" || name == "unalias" || strings.ContainsAny(name, ";$'\"") {
+		return fmt.Errorf("%s can't be an alias", name)
+	}
+	if _, ok := player.Aliases[name]; !ok && len(player.Aliases) >= players.MaxAliases {
+		return fmt.Errorf("you can't have more than %d aliases", players.MaxAliases)
+	}
+
+	aliases := make(map[string]string, len(player.Aliases)+1)
+	for existing, existingExpansion := range player.Aliases {
+		aliases[existing] = existingExpansion
+	}
+	aliases[name] = expansion
+	_, err := expandAliases(aliases, []string{name})
+	return err
+}
+
+func (h *AliasCommandHandler) list(player *players.Player) {
+	if len(player.Aliases) == 0 {
+		display.PrintWithColor(player, "You haven't made any aliases.\n", "reset")
+		return
+	}
+
+	names := make([]string, 0, len(player.Aliases))
+	for name := range player.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	display.PrintWithColor(player, "Your aliases:\n", "reset")
+	for _, name := range names {
+		display.PrintWithColor(player, fmt.Sprintf("%-12s %s\n", name, player.Aliases[name]), "primary")
+	}
+}
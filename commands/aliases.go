@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxAliasDepth is how many aliases deep an alias can call other aliases.
+	maxAliasDepth = 10
+	// maxAliasCommands is how many commands one line can expand into, so a few
+	// aliases which each run another several times can't flood the game.
+	maxAliasCommands = 50
+)
+
+// quoteArgument puts quotes back around an argument which had them, so that
+// it's still one argument once it's put in an alias.
+func quoteArgument(argument string) string {
+	if strings.ContainsAny(argument, " \t") {
+		return `"` + argument + `"`
+	}
+	return argument
+}
+
+// substituteArguments fills in the alias, $1 to $9 are the arguments, $* all
+// of them as typed and $$ a $.  An alias which doesn't use any of them has the
+// arguments put on the end, so "k" for "kill" works as "k goblin".
+func substituteArguments(expansion string, command string) string {
+	arguments := Tokenize(command)[1:]
+	all := restOfLine(command, 1)
+
+	var expanded strings.Builder
+	used := false
+	for i := 0; i < len(expansion); i++ {
+		if expansion[i] != '$' || i+1 == len(expansion) {
+			expanded.WriteByte(expansion[i])
+			continue
+		}
+
+		next := expansion[i+1]
+		switch {
+		case next == '$':
+			expanded.WriteByte('$')
+		case next == '*':
+			expanded.WriteString(all)
+			used = true
+		case next >= '1' && next <= '9':
+			n, _ := strconv.Atoi(string(next))
+			if n <= len(arguments) {
+				expanded.WriteString(quoteArgument(arguments[n-1]))
+			}
+			used = true
+		default:
+			expanded.WriteByte('$')
+			continue
+		}
+		i++
+	}
+
+	if !used && all != "" {
+		expanded.WriteString(" " + all)
+	}
+	return expanded.String()
+}
+
+// aliasName is the alias a command would run, if it's one of the aliases.
+func aliasName(aliases map[string]string, command string) (string, bool) {
+	words := Tokenize(command)
+	if len(words) == 0 {
+		return "", false
+	}
+	name := strings.ToLower(words[0])
+	_, ok := aliases[name]
+	return name, ok
+}
+
+// expandAliases turns the commands which are aliases into what they stand for,
+// which can be more aliases.  An alias which ends up calling itself is an error
+// rather than a loop.
+func expandAliases(aliases map[string]string, commands []string) ([]string, error) {
+	var expanded []string
+	err := expandInto(aliases, commands, nil, &expanded)
+	return expanded, err
+}
+
+func expandInto(aliases map[string]string, commands []string, calling []string, expanded *[]string) error {
+	for _, command := range commands {
+		command = expandShortcuts(strings.TrimSpace(command))
+		name, ok := aliasName(aliases, command)
+		if !ok {
+			if command != "" {
+				if len(*expanded) == maxAliasCommands {
+					return fmt.Errorf("that runs more than %d commands", maxAliasCommands)
+				}
+				*expanded = append(*expanded, command)
+			}
+			continue
+		}
+
+		for _, caller := range calling {
+			if caller == name {
+				return fmt.Errorf("the alias %s calls itself (%s)", name, strings.Join(append(calling, name), " -> "))
+			}
+		}
+		if len(calling) == maxAliasDepth {
+			return fmt.Errorf("the alias %s goes more than %d aliases deep", calling[0], maxAliasDepth)
+		}
+
+		body := substituteArguments(aliases[name], command)
+		err := expandInto(aliases, splitCommands(body), append(calling, name), expanded)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
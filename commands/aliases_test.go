@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubstituteArguments(t *testing.T) {
+	tests := []struct {
+		expansion string
+		command   string
+		expected  string
+	}{
+		{"kill $1;look", "kl goblin", "kill goblin;look"},
+		{"kill", "k goblin", "kill goblin"},
+		{"say $*", `s Hello, "friend"`, `say Hello, "friend"`},
+		{"give $2 to $1", `gv Bob "long sword"`, `give "long sword" to Bob`},
+		{"say $3", "s one", "say "},
+		{"say costs $$5", "s", "say costs $5"},
+		{"say $x", "s", "say $x"},
+	}
+	for _, test := range tests {
+		if got := substituteArguments(test.expansion, test.command); got != test.expected {
+			t.Errorf("substituteArguments(%q, %q) = %q, expected %q", test.expansion, test.command, got, test.expected)
+		}
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"kl":   "kill $1;look",
+		"k":    "kill",
+		"hunt": "kl $1;'found $1",
+	}
+
+	got, err := expandAliases(aliases, []string{"HUNT goblin", "north"})
+	if err != nil {
+		t.Fatalf("expandAliases() returned %v", err)
+	}
+	expected := []string{"kill goblin", "look", "' found goblin", "north"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("expandAliases() = %q, expected %q", got, expected)
+	}
+}
+
+func TestExpandAliasesStopsRecursion(t *testing.T) {
+	aliases := map[string]string{
+		"a": "look;b",
+		"b": "a",
+	}
+	if _, err := expandAliases(aliases, []string{"a"}); err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("expandAliases() with a loop returned %v", err)
+	}
+
+	// an alias can be used twice, as long as it doesn't call itself
+	aliases = map[string]string{"l": "look", "ll": "l;l"}
+	if got, err := expandAliases(aliases, []string{"ll"}); err != nil || len(got) != 2 {
+		t.Errorf("expandAliases() = %q, %v", got, err)
+	}
+
+	// nor flood the game by doubling up
+	aliases = map[string]string{
+		"x1":  "look",
+		"x2":  "x1;x1",
+		"x4":  "x2;x2",
+		"x8":  "x4;x4",
+		"x16": "x8;x8",
+		"x32": "x16;x16",
+		"x64": "x32;x32",
+	}
+	if _, err := expandAliases(aliases, []string{"x64"}); err == nil {
+		t.Errorf("expandAliases() ran more than %d commands", maxAliasCommands)
+	}
+}
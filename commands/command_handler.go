@@ -18,30 +18,390 @@ type CommandHandlerWithPriority struct {
 	Notifier   notifications.Notifier
 	WorldState worldState.WorldState
 	Priority   int
+	// commands with lag are queued up on the area's beat, and the player has to
+	// wait that many ticks before their next queued command runs
+	Lag int
+	// queued commands run on the area's goroutine even without lag, as they
+	// change what's lying in the room or what players carry, which the area
+	// changes too, ie when corpses decay
+	Queued bool
+	// commands with a role are hidden from anyone who doesn't have it
+	Role players.Role
+
+	// what help shows for the command.  Syntax and Summary are one line each,
+	// and commands are listed under their Category.
+	Syntax      string
+	Summary     string
+	Description string
+	Category    string
 }
 
+// The categories commands are listed under by help and commands.
+const (
+	CategoryMovement      = "Movement"
+	CategoryCommunication = "Communication"
+	CategoryInformation   = "Information"
+	CategoryItems         = "Items"
+	CategoryCombat        = "Combat"
+	CategorySettings      = "Settings"
+	CategoryAdmin         = "Admin"
+)
+
 var CommandHandlers = map[string]CommandHandlerWithPriority{
-	"north":      {Handler: &MovePlayerCommandHandler{Direction: "north"}, Priority: 1},
-	"south":      {Handler: &MovePlayerCommandHandler{Direction: "south"}, Priority: 1},
-	"west":       {Handler: &MovePlayerCommandHandler{Direction: "west"}, Priority: 1},
-	"east":       {Handler: &MovePlayerCommandHandler{Direction: "east"}, Priority: 1},
-	"up":         {Handler: &MovePlayerCommandHandler{Direction: "up"}, Priority: 1},
-	"down":       {Handler: &MovePlayerCommandHandler{Direction: "down"}, Priority: 1},
-	"say":        {Handler: &SayHandler{}, Priority: 2},
-	"'":          {Handler: &SayHandler{}, Priority: 2},
-	"tell":       {Handler: &TellHandler{}, Priority: 2},
-	"give":       {Handler: &GiveCommandHandler{}, Priority: 2},
-	"look":       {Handler: &LookCommandHandler{}, Priority: 2},
-	"area":       {Handler: &AreaCommandHandler{}, Priority: 2},
-	"logout":     {Handler: &LogoutCommandHandler{}, Priority: 10},
-	"exits":      {Handler: &ExitsCommandHandler{}, Priority: 2},
-	"take":       {Handler: &TakeCommandHandler{}, Priority: 3},
-	"drop":       {Handler: &DropCommandHandler{}, Priority: 2},
-	"inventory":  {Handler: &InventoryCommandHandler{}, Priority: 2},
-	"foo":        {Handler: &FooCommandHandler{}, Priority: 2},
-	"/sethealth": {Handler: &AdminSetHealthCommandHandler{}, Priority: 10},
-	"status":     {Handler: &PlayerStatusCommandHandler{}, Priority: 2},
-	"equip":      {Handler: &EquipHandler{}, Priority: 2},
-	"remove":     {Handler: &RemoveCommandHandler{}, Priority: 2},
-	"whoami":     {Handler: &WhoAmICommandHandler{}, Priority: 10},
+	"north": {
+		Handler:     &MovePlayerCommandHandler{Direction: "north"},
+		Priority:    1,
+		Lag:         1,
+		Syntax:      "north",
+		Summary:     "Walk north.",
+		Description: "Moves you through the exit to the north, if there is one.  Any command which starts the same way works too, ie n.",
+		Category:    CategoryMovement,
+	},
+	"south": {
+		Handler:     &MovePlayerCommandHandler{Direction: "south"},
+		Priority:    1,
+		Lag:         1,
+		Syntax:      "south",
+		Summary:     "Walk south.",
+		Description: "Moves you through the exit to the south, if there is one.  Any command which starts the same way works too, ie s.",
+		Category:    CategoryMovement,
+	},
+	"west": {
+		Handler:     &MovePlayerCommandHandler{Direction: "west"},
+		Priority:    1,
+		Lag:         1,
+		Syntax:      "west",
+		Summary:     "Walk west.",
+		Description: "Moves you through the exit to the west, if there is one.  Any command which starts the same way works too, ie w.",
+		Category:    CategoryMovement,
+	},
+	"east": {
+		Handler:     &MovePlayerCommandHandler{Direction: "east"},
+		Priority:    1,
+		Lag:         1,
+		Syntax:      "east",
+		Summary:     "Walk east.",
+		Description: "Moves you through the exit to the east, if there is one.  Any command which starts the same way works too, ie e.",
+		Category:    CategoryMovement,
+	},
+	"up": {
+		Handler:     &MovePlayerCommandHandler{Direction: "up"},
+		Priority:    1,
+		Lag:         1,
+		Syntax:      "up",
+		Summary:     "Climb up.",
+		Description: "Moves you through the exit leading up, if there is one.",
+		Category:    CategoryMovement,
+	},
+	"down": {
+		Handler:     &MovePlayerCommandHandler{Direction: "down"},
+		Priority:    1,
+		Lag:         1,
+		Syntax:      "down",
+		Summary:     "Climb down.",
+		Description: "Moves you through the exit leading down, if there is one.",
+		Category:    CategoryMovement,
+	},
+	"say": {
+		Handler:     &SayHandler{},
+		Priority:    2,
+		Syntax:      "say <message>",
+		Summary:     "Say something to everyone in the room.",
+		Description: "Everyone in the room hears what you say, exactly as you typed it.  ' works the same way, ie 'hello.",
+		Category:    CategoryCommunication,
+	},
+	"'": {
+		Handler:     &SayHandler{},
+		Priority:    2,
+		Syntax:      "'<message>",
+		Summary:     "Say something to everyone in the room.",
+		Description: "A shorter way of typing say, ie 'hello.",
+		Category:    CategoryCommunication,
+	},
+	"tell": {
+		Handler:     &TellHandler{},
+		Priority:    2,
+		Syntax:      "tell <player> <message>",
+		Summary:     "Say something to one player, wherever they are.",
+		Description: "Sends a private message to a player who is logged in.  The start of their name is enough, as long as nobody else's name starts the same way.",
+		Category:    CategoryCommunication,
+	},
+	"give": {
+		Handler:     &GiveCommandHandler{},
+		Priority:    2,
+		Queued:      true,
+		Syntax:      "give <item> [to] <player>",
+		Summary:     "Hand an item to someone in the room.",
+		Description: "Gives an item from your inventory to another player in the same room.  Quote items with more than one word, ie give \"long sword\" to Bob, and use 2.sword for the second sword.",
+		Category:    CategoryItems,
+	},
+	"look": {
+		Handler:     &LookCommandHandler{},
+		Priority:    2,
+		Queued:      true,
+		Syntax:      "look [<direction>|[at] <target>|in <container>]",
+		Summary:     "Look around, or at something.",
+		Description: "On its own, shows the room you're in.  Give it a direction to see what's through an exit, a player, mob or item to look at them, or in <container> to see what's inside.",
+		Category:    CategoryInformation,
+	},
+	"area": {
+		Handler:     &AreaCommandHandler{},
+		Priority:    2,
+		Syntax:      "area",
+		Summary:     "Show which area you are in.",
+		Description: "Shows the name and description of the area around you.",
+		Category:    CategoryInformation,
+	},
+	"logout": {
+		Handler:     &LogoutCommandHandler{},
+		Priority:    10,
+		Syntax:      "logout",
+		Summary:     "Leave the game.",
+		Description: "Saves your character and disconnects you.",
+		Category:    CategorySettings,
+	},
+	"exits": {
+		Handler:     &ExitsCommandHandler{},
+		Priority:    2,
+		Syntax:      "exits",
+		Summary:     "List the ways out of the room.",
+		Description: "Shows each exit from the room you're in and the name of the room it leads to.",
+		Category:    CategoryInformation,
+	},
+	"take": {
+		Handler:     &TakeCommandHandler{},
+		Priority:    3,
+		Queued:      true,
+		Syntax:      "take <item>|all [from <container>]",
+		Summary:     "Pick something up.",
+		Description: "Takes an item from the floor, or from a container when you add from <container>.  all takes everything, and all.coin every coin.",
+		Category:    CategoryItems,
+	},
+	"drop": {
+		Handler:     &DropCommandHandler{},
+		Priority:    2,
+		Queued:      true,
+		Syntax:      "drop <item>|all",
+		Summary:     "Put something down.",
+		Description: "Drops an item from your inventory onto the floor.  all drops everything, and all.coin every coin.",
+		Category:    CategoryItems,
+	},
+	"inventory": {
+		Handler:     &InventoryCommandHandler{},
+		Priority:    2,
+		Queued:      true,
+		Syntax:      "inventory",
+		Summary:     "List what you are carrying.",
+		Description: "Shows every item in your inventory.  Equipped items are listed by equip.",
+		Category:    CategoryItems,
+	},
+	"foo": {
+		Handler:     &FooCommandHandler{},
+		Priority:    2,
+		Syntax:      "foo",
+		Summary:     "Send a test action to your area.",
+		Description: "Queues a do-nothing action on your area, to check that its loop is running.",
+		Category:    CategoryInformation,
+	},
+	"/sethealth": {
+		Handler:     &AdminSetHealthCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleAdmin,
+		Syntax:      "/sethealth <player> <hp>",
+		Summary:     "Set a player's health.",
+		Description: "Sets the player's hit points to the value, whether or not they're logged in.",
+		Category:    CategoryAdmin,
+	},
+	"status": {
+		Handler:     &PlayerStatusCommandHandler{},
+		Priority:    2,
+		Syntax:      "status",
+		Summary:     "Show your class, race and abilities.",
+		Description: "Shows your character class, race and each of your ability scores.",
+		Category:    CategoryInformation,
+	},
+	"equip": {
+		Handler:     &EquipHandler{},
+		Priority:    2,
+		Queued:      true,
+		Syntax:      "equip [<item>]",
+		Summary:     "Wear or wield an item.",
+		Description: "On its own, lists what you have equipped.  Otherwise moves the item from your inventory into the slot it belongs in.",
+		Category:    CategoryItems,
+	},
+	"remove": {
+		Handler:     &RemoveCommandHandler{},
+		Priority:    2,
+		Queued:      true,
+		Syntax:      "remove <item>",
+		Summary:     "Take off an equipped item.",
+		Description: "Moves an item you have equipped back into your inventory.",
+		Category:    CategoryItems,
+	},
+	"whoami": {
+		Handler:     &WhoAmICommandHandler{},
+		Priority:    10,
+		Syntax:      "whoami",
+		Summary:     "Show your character's name.",
+		Description: "Prints the name of the character you are playing.",
+		Category:    CategoryInformation,
+	},
+	"kill": {
+		Handler:     &KillCommandHandler{},
+		Priority:    2,
+		Lag:         2,
+		Syntax:      "kill <target>",
+		Summary:     "Attack a mob in the room.",
+		Description: "Starts a fight with the mob, which goes on until one of you dies or flees.",
+		Category:    CategoryCombat,
+	},
+	"flee": {
+		Handler:     &FleeCommandHandler{},
+		Priority:    1,
+		Lag:         2,
+		Syntax:      "flee",
+		Summary:     "Run away from a fight.",
+		Description: "Leaves the fight through a random exit.  It won't work when there's nowhere to run.",
+		Category:    CategoryCombat,
+	},
+	"keys": {
+		Handler:     &KeysCommandHandler{},
+		Priority:    5,
+		Syntax:      "keys [list|add [<public key>]|remove <number>]",
+		Summary:     "Manage the ssh keys which log you in.",
+		Description: "An ssh key you add logs you straight in, without a password.  keys add on its own adds the key you connected with.",
+		Category:    CategorySettings,
+	},
+	"help": {
+		Handler:     &HelpCommandHandler{},
+		Priority:    5,
+		Syntax:      "help [<command>|<topic>]",
+		Summary:     "Find out how to play.",
+		Description: "On its own, lists the commands you can use and the help topics.  help <command> shows how to use a command, and help <topic> explains something else about the game.  The start of a name is enough.",
+		Category:    CategoryInformation,
+	},
+	"commands": {
+		Handler:     &CommandsCommandHandler{},
+		Priority:    5,
+		Syntax:      "commands",
+		Summary:     "List every command you can use.",
+		Description: "Lists each command you can use with a line about what it does, by category.",
+		Category:    CategoryInformation,
+	},
+	"alias": {
+		Handler:     &AliasCommandHandler{},
+		Priority:    5,
+		Syntax:      "alias [list|<name> [<commands>]]",
+		Summary:     "Save commands under a shorter name.",
+		Description: "alias kl kill $1;look makes kl goblin run kill goblin then look.  $1 to $9 are replaced with the alias's arguments and $* with all of them, and without any the arguments go on the end.  alias <name> shows what an alias runs.",
+		Category:    CategorySettings,
+	},
+	"unalias": {
+		Handler:     &UnaliasCommandHandler{},
+		Priority:    5,
+		Syntax:      "unalias <name>",
+		Summary:     "Remove an alias.",
+		Description: "Deletes one of the aliases made with alias.",
+		Category:    CategorySettings,
+	},
+	"prompt": {
+		Handler:     &PromptCommandHandler{},
+		Priority:    5,
+		Syntax:      "prompt [<template>|default]",
+		Summary:     "Change what your prompt shows.",
+		Description: "On its own, shows your prompt and what it can show.  %h and %H are your hit points and most hit points, %v and %V your movement, %r the room, %a the area, %e the exits, %c how healthy whoever you're fighting is and %t the time of day, ie prompt \"<%h/%Hhp %v/%Vmv %e> \".  prompt default puts it back.",
+		Category:    CategorySettings,
+	},
+	"/goto": {
+		Handler:     &GotoCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleBuilder,
+		Syntax:      "/goto <room uuid|player>",
+		Summary:     "Go to a room, or to a player.",
+		Description: "Takes you straight to the room, or to the room the player is in.",
+		Category:    CategoryAdmin,
+	},
+	"/transfer": {
+		Handler:     &TransferCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleModerator,
+		Syntax:      "/transfer <player> <room uuid>",
+		Summary:     "Send a player to a room.",
+		Description: "Moves a player who is logged in to the room.",
+		Category:    CategoryAdmin,
+	},
+	"/summon": {
+		Handler:     &SummonCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleModerator,
+		Syntax:      "/summon <player>",
+		Summary:     "Bring a player to you.",
+		Description: "Moves a player who is logged in to the room you're in.",
+		Category:    CategoryAdmin,
+	},
+	"/restore": {
+		Handler:     &RestoreCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleModerator,
+		Syntax:      "/restore [<player>]",
+		Summary:     "Heal a player completely.",
+		Description: "Restores a player's health and movement, or your own without a player.",
+		Category:    CategoryAdmin,
+	},
+	"/purge": {
+		Handler:     &PurgeCommandHandler{},
+		Priority:    10,
+		Lag:         1,
+		Role:        players.RoleBuilder,
+		Syntax:      "/purge room",
+		Summary:     "Destroy every mob and item in the room.",
+		Description: "Deletes every mob and item on the floor of the room you're in.  Players and what they carry are left alone.",
+		Category:    CategoryAdmin,
+	},
+	"/load": {
+		Handler:     &LoadCommandHandler{},
+		Priority:    10,
+		Lag:         1,
+		Role:        players.RoleBuilder,
+		Syntax:      "/load mob <slug>|item <template>",
+		Summary:     "Create a mob or an item.",
+		Description: "Creates a mob from its template, or an item from its template, in the room you're in.",
+		Category:    CategoryAdmin,
+	},
+	"/force": {
+		Handler:     &ForceCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleAdmin,
+		Syntax:      "/force <player> <command>",
+		Summary:     "Make a player run a command.",
+		Description: "The player runs the command as if they had typed it, and still needs the role for it.  Admins can't be forced.",
+		Category:    CategoryAdmin,
+	},
+	"/shutdown": {
+		Handler:     &ShutdownCommandHandler{},
+		Priority:    20,
+		Role:        players.RoleAdmin,
+		Syntax:      "/shutdown [<minutes>|cancel]",
+		Summary:     "Shut the server down.",
+		Description: "Warns everyone, then shuts the server down after the number of minutes, or right away without one.  cancel stops the countdown.",
+		Category:    CategoryAdmin,
+	},
+	"/reboot": {
+		Handler:     &ShutdownCommandHandler{Reboot: true},
+		Priority:    20,
+		Role:        players.RoleAdmin,
+		Syntax:      "/reboot [<minutes>|cancel]",
+		Summary:     "Restart the server.",
+		Description: "Warns everyone, then restarts the server after the number of minutes, or right away without one.  Players stay connected where the server supports it.  cancel stops the countdown.",
+		Category:    CategoryAdmin,
+	},
+	"/audit": {
+		Handler:     &AuditCommandHandler{},
+		Priority:    10,
+		Role:        players.RoleAdmin,
+		Syntax:      "/audit [<player>]",
+		Summary:     "List the latest admin commands.",
+		Description: "Shows the most recent commands which needed more than being a player, everyone's or just one player's.",
+		Category:    CategoryAdmin,
+	},
 }
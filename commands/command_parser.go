@@ -15,22 +15,31 @@ type UsesWorldState interface {
 	SetWorldState(worldState *worldState.WorldState)
 }
 
+// UsesRouter is for commands which run other commands, ie /force.
+type UsesRouter interface {
+	SetRouter(router *CommandRouter)
+}
+
 type CommandParser struct {
 	commandName string
 	arguments   []string
 }
 
 func NewCommandParser(commandString string, CommandHandlers map[string]CommandHandlerWithPriority) *CommandParser {
-	// Split the command string into the command name and arguments.
-	commandParts := strings.Split(commandString, " ")
-	commandName := commandParts[0]
+	// Split the command string into the command name and arguments, which keep
+	// the case they were typed in.
+	commandParts := Tokenize(expandShortcuts(commandString))
+	if len(commandParts) == 0 {
+		return &CommandParser{}
+	}
+	commandName := strings.ToLower(commandParts[0])
 	arguments := commandParts[1:]
 
 	// look for partial commands ie "n" for "north"
 	bestMatch := ""
 	highestPriority := math.MaxInt32
 	for fullCommand, handlerWithPriority := range CommandHandlers {
-		if strings.HasPrefix(strings.ToLower(fullCommand), commandParts[0]) && handlerWithPriority.Priority < highestPriority {
+		if strings.HasPrefix(strings.ToLower(fullCommand), commandName) && handlerWithPriority.Priority < highestPriority {
 			bestMatch = fullCommand
 			highestPriority = handlerWithPriority.Priority
 		}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/players"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CommandsCommandHandler lists each command the player can use with a line
+// about what it does, by category.
+type CommandsCommandHandler struct {
+	Router *CommandRouter
+}
+
+func (h *CommandsCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	available := h.Router.availableCommands(player)
+	byCategory, categories := commandsByCategory(available)
+	for _, category := range categories {
+		var elements []string
+		for _, name := range byCategory[category] {
+			elements = append(elements, fmt.Sprintf("%s - %s", name, available[name].Summary))
+		}
+		printHelpMenu(player, category, elements)
+	}
+}
+
+func (h *CommandsCommandHandler) SetRouter(router *CommandRouter) {
+	h.Router = router
+}
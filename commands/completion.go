@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"mud/areas"
+	"mud/players"
+	"strings"
+)
+
+// fillerWords aren't worth completing, nobody types "the" to mean the thing.
+var fillerWords = map[string]bool{"a": true, "an": true, "the": true, "of": true}
+
+// Complete suggests what the last word of the line could be: the commands
+// the player can use for the first word, and the names of what they can see
+// in the room after that.
+func (r *CommandRouter) Complete(player *players.Player, room *areas.Room, line string) []string {
+	if strings.TrimSpace(line) == "" || len(Tokenize(line)) == 1 && !strings.HasSuffix(line, " ") {
+		return r.commandNames(player)
+	}
+	if room == nil {
+		return nil
+	}
+
+	var names []string
+	for _, other := range room.GetPlayers() {
+		if other.UUID != player.UUID {
+			names = append(names, other.Name)
+		}
+	}
+	for _, mob := range room.GetMobs() {
+		names = append(names, mob.GetName())
+	}
+	for _, item := range room.GetItems() {
+		names = append(names, item.GetName())
+	}
+
+	var words []string
+	for _, name := range names {
+		for _, word := range strings.Fields(name) {
+			if !fillerWords[strings.ToLower(word)] {
+				words = append(words, strings.ToLower(word))
+			}
+		}
+	}
+	return words
+}
+
+func (r *CommandRouter) commandNames(player *players.Player) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for command := range r.Handlers {
+		if player.HasRole(r.Roles[command]) {
+			names = append(names, command)
+		}
+	}
+	return names
+}
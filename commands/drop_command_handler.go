@@ -21,29 +21,32 @@ func (h *DropCommandHandler) SetWorldState(world_state *world_state.WorldState)
 }
 
 func (h *DropCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) == 0 {
+		display.PrintWithColor(player, "Drop what?\n", "reset")
+		return
+	}
+
 	roomUUID := player.RoomUUID
 	room := h.WorldState.GetRoom(roomUUID, false)
 
-	playerItems := player.Inventory
-	if len(playerItems) > 0 {
-		for _, item := range playerItems {
-			if item.GetName() == arguments[0] {
-				if err := player.RemoveItem(item); err != nil {
-					fmt.Printf("error removing item: %s", err)
-				}
-				room.AddItem(db, item)
-				query := "UPDATE item_locations SET room_uuid = ?, player_uuid = NULL WHERE item_uuid = ?"
-				_, err := db.Exec(query, roomUUID, item.GetUUID())
-				if err != nil {
-					display.PrintWithColor(player, fmt.Sprintf("Failed to update item location: %v\n", err), "danger")
-				}
-				display.PrintWithColor(player, fmt.Sprintf("You drop the %s.\n", item.GetName()), "reset")
-				h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s dropped %s.\n", player.Name, item.Name))
-				break
-			}
-		}
-	} else {
+	found := Resolve(ParseTarget(arguments...), player.Inventory)
+	if len(found) == 0 {
 		display.PrintWithColor(player, "You don't have that item.\n", "warning")
+		return
+	}
+
+	for _, item := range found {
+		if err := player.RemoveItem(item); err != nil {
+			fmt.Printf("error removing item: %s", err)
+			continue
+		}
+		// adding it to the room moves it there in the database too
+		err := room.AddItem(db, item)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Failed to update item location: %v\n", err), "danger")
+		}
+		display.PrintWithColor(player, fmt.Sprintf("You drop the %s.\n", item.GetName()), "reset")
+		h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s dropped %s.\n", player.Name, item.Name))
 	}
 }
 
@@ -20,21 +20,20 @@ func (h *EquipHandler) Execute(db *sqlx.DB, player *players.Player, command stri
 		return
 	}
 
-	playerItems := player.Inventory
+	found := Resolve(ParseTarget(arguments...), player.Inventory)
+	if len(found) == 0 {
+		display.PrintWithColor(player, "You don't have that item.\n", "warning")
+		return
+	}
 
-	if len(playerItems) > 0 {
-		for _, item := range playerItems {
-			if item.GetName() == arguments[0] {
-				if player.Equip(db, item) {
-					display.PrintWithColor(player, fmt.Sprintf("You wield %s.\n", item.GetName()), "reset")
-					h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s wields %s.\n", player.Name, item.Name))
-					player.RemoveItem(item)
-				}
-				break
-			}
+	for _, item := range found {
+		if !player.Equip(db, item) {
+			display.PrintWithColor(player, fmt.Sprintf("You can't equip %s.\n", item.GetName()), "reset")
+			continue
 		}
-	} else {
-		display.PrintWithColor(player, "You don't have that item.\n", "warning")
+		display.PrintWithColor(player, fmt.Sprintf("You wield %s.\n", item.GetName()), "reset")
+		h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s wields %s.\n", player.Name, item.Name))
+		player.RemoveItem(item)
 	}
 }
 
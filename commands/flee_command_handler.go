@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"math/rand"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type FleeCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+}
+
+func (h *FleeCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	areaUUID := player.AreaUUID
+	currentRoom := h.WorldState.GetRoom(player.RoomUUID, true)
+
+	if currentRoom.Combat == nil || !currentRoom.Combat.Contains(player) {
+		display.PrintWithColor(player, "You aren't fighting anyone.\n", "reset")
+		return
+	}
+
+	exits := currentRoom.Exits
+	exitMap := map[string]*areas.Room{
+		"north": exits.GetNorth(),
+		"south": exits.GetSouth(),
+		"west":  exits.GetWest(),
+		"east":  exits.GetEast(),
+		"up":    exits.GetUp(),
+		"down":  exits.GetDown(),
+	}
+
+	var directions []string
+	for direction, exit := range exitMap {
+		if exit != nil && exit.UUID != "" {
+			directions = append(directions, direction)
+		}
+	}
+	if len(directions) == 0 {
+		display.PrintWithColor(player, "There's nowhere to run!\n", "warning")
+		return
+	}
+
+	direction := directions[rand.Intn(len(directions))]
+	currentRoom.Combat.Remove(player)
+
+	display.PrintWithColor(player, fmt.Sprintf("You flee %s!\n", direction), "warning")
+	h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s flees from combat!\n", player.Name))
+	movePlayerToDirection(h.WorldState, db, player, exitMap[direction], direction, h.Notifier, h.WorldState, currentChannel, updateChannel)
+
+	if areaUUID != player.AreaUUID {
+		updateChannel(player.AreaUUID)
+	}
+}
+
+func (h *FleeCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *FleeCommandHandler) SetWorldState(world_state *world_state.WorldState) {
+	h.WorldState = world_state
+}
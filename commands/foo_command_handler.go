@@ -13,7 +13,7 @@ type FooCommandHandler struct {
 }
 
 func (h *FooCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
-	currentChannel <- areas.Action{Player: *player, Command: command, Arguments: arguments}
+	currentChannel <- areas.Action{Player: player, Command: command, Arguments: arguments}
 }
 
 func (h *FooCommandHandler) SetNotifier(notifier *notifications.Notifier) {
@@ -16,30 +16,51 @@ type GiveCommandHandler struct {
 	WorldState *world_state.WorldState
 }
 
-func (h *GiveCommandHandler) SetNotifier(notifier *notifications.Notifier, world_state *world_state.WorldState) {
+func (h *GiveCommandHandler) SetNotifier(notifier *notifications.Notifier) {
 	h.Notifier = notifier
+}
+
+func (h *GiveCommandHandler) SetWorldState(world_state *world_state.WorldState) {
 	h.WorldState = world_state
 }
 
 func (h *GiveCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
-	item := player.GetItemFromInventory(arguments[0])
-	if item == nil {
-		display.PrintWithColor(player, "You don't have that item", "reset")
+	// give <item> to <player>, or give <item> <player>
+	itemWords, recipientWords, found := splitAt(arguments, "to")
+	if !found && len(arguments) > 1 {
+		itemWords, recipientWords = arguments[:len(arguments)-1], arguments[len(arguments)-1:]
+	}
+	if len(itemWords) == 0 || len(recipientWords) == 0 {
+		display.PrintWithColor(player, "Give what to whom?\n", "reset")
 		return
 	}
 
-	currentRoomUUID := player.RoomUUID
-	currentRoom := h.WorldState.GetRoom(currentRoomUUID, false)
+	given := Resolve(ParseTarget(itemWords...), player.Inventory)
+	if len(given) == 0 {
+		display.PrintWithColor(player, "You don't have that item.\n", "reset")
+		return
+	}
 
-	recipient := currentRoom.GetPlayerByName(arguments[1])
-	if recipient == nil {
-		display.PrintWithColor(player, "You don't see them here", "reset")
+	currentRoom := h.WorldState.GetRoom(player.RoomUUID, false)
+	var others []*players.Player
+	for _, playerInRoom := range currentRoom.GetPlayers() {
+		if playerInRoom.UUID != player.UUID {
+			others = append(others, playerInRoom)
+		}
+	}
+	recipientTarget := ParseTarget(recipientWords...)
+	recipients := Resolve(recipientTarget, others)
+	if len(recipients) == 0 || recipientTarget.All {
+		display.PrintWithColor(player, "You don't see them here.\n", "reset")
 		return
 	}
+	recipient := recipients[0]
 
-	player.RemoveItem(item)
-	recipient.AddItem(db, item)
+	for _, item := range given {
+		player.RemoveItem(item)
+		recipient.AddItem(db, item)
 
-	display.PrintWithColor(player, fmt.Sprintf("You give %s to %s\n", item.GetName(), recipient.Name), "reset")
-	h.Notifier.NotifyPlayer(recipient.UUID, fmt.Sprintf("\n%s gives you %s\n", player.Name, item.GetName()))
+		display.PrintWithColor(player, fmt.Sprintf("You give %s to %s.\n", item.GetName(), recipient.Name), "reset")
+		h.Notifier.NotifyPlayer(recipient.UUID, fmt.Sprintf("\n%s gives you %s.\n", player.Name, item.GetName()))
+	}
 }
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"mud/utilities"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A token is one word of a command, and where it was in what was typed.
+type token struct {
+	text  string
+	start int
+}
+
+func isQuote(r byte) bool {
+	return r == '"' || r == '\''
+}
+
+// tokenize splits a command into words.  Quotes at the start of a word keep
+// everything up to the closing quote together, so `take "long sword" from
+// chest` is take, long sword, from and chest.  A quote without a closing one,
+// or in the middle of a word like don't, is just part of the word.
+func tokenize(input string) []token {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		if unicode.IsSpace(rune(input[i])) {
+			i++
+			continue
+		}
+
+		start := i
+		if isQuote(input[i]) && i+1 < len(input) {
+			if end := strings.IndexByte(input[i+1:], input[i]); end > 0 {
+				tokens = append(tokens, token{text: input[i+1 : i+1+end], start: start})
+				i += end + 2
+				continue
+			}
+		}
+		for i < len(input) && !unicode.IsSpace(rune(input[i])) {
+			i++
+		}
+		tokens = append(tokens, token{text: input[start:i], start: start})
+	}
+	return tokens
+}
+
+// Tokenize returns the words of a command, see tokenize.
+func Tokenize(input string) []string {
+	tokens := tokenize(input)
+	words := make([]string, len(tokens))
+	for idx, token := range tokens {
+		words[idx] = token.text
+	}
+	return words
+}
+
+// splitCommands splits what was typed into the commands separated by ;,
+// leaving any ; in double quotes alone.  Single quotes are too often
+// apostrophes to be trusted with it.
+func splitCommands(input string) []string {
+	var commands []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(input); i++ {
+		switch {
+		case quote != 0:
+			if input[i] == quote {
+				quote = 0
+			}
+		case input[i] == '"' && strings.IndexByte(input[i+1:], '"') >= 0:
+			quote = '"'
+		case input[i] == ';':
+			commands = append(commands, input[start:i])
+			start = i + 1
+		}
+	}
+	return append(commands, input[start:])
+}
+
+// expandShortcuts turns the commands which are a single character stuck on
+// the front of the rest into separate words, ie 'hello into ' hello.
+func expandShortcuts(command string) string {
+	if strings.HasPrefix(command, "'") && len(command) > 1 && command[1] != ' ' {
+		return "' " + command[1:]
+	}
+	return command
+}
+
+// restOfLine is the command as it was typed after the first n words, for the
+// commands which pass on what the player wrote, ie say and tell.
+func restOfLine(command string, n int) string {
+	tokens := tokenize(command)
+	if n >= len(tokens) {
+		return ""
+	}
+	return strings.TrimSpace(command[tokens[n].start:])
+}
+
+// A Target is what a command is aimed at: "sword", "2.sword" for the second
+// sword, "all" for everything or "all.sword" for every sword.
+type Target struct {
+	Keyword string
+	// which of the matches, starting at 1
+	Ordinal int
+	All     bool
+}
+
+// ParseTarget reads a target from the words naming it.
+func ParseTarget(words ...string) Target {
+	keyword := strings.Join(words, " ")
+	if strings.EqualFold(keyword, "all") {
+		return Target{All: true}
+	}
+
+	prefix, rest, found := strings.Cut(keyword, ".")
+	if !found || rest == "" {
+		return Target{Keyword: keyword, Ordinal: 1}
+	}
+	if strings.EqualFold(prefix, "all") {
+		return Target{Keyword: rest, All: true}
+	}
+	if ordinal, err := strconv.Atoi(prefix); err == nil && ordinal > 0 {
+		return Target{Keyword: rest, Ordinal: ordinal}
+	}
+	return Target{Keyword: keyword, Ordinal: 1}
+}
+
+// A Named is anything a target can pick out, ie items, mobs and players.
+type Named interface {
+	GetName() string
+}
+
+// Matches checks whether the target could mean the thing, ignoring which one
+// of them it asked for.
+func (target Target) Matches(thing Named) bool {
+	if target.Keyword == "" {
+		return target.All
+	}
+	return utilities.MatchesName(thing.GetName(), target.Keyword)
+}
+
+// Resolve returns what the target picks out of the candidates, every match for
+// all, otherwise the one the ordinal asks for.  It's empty when nothing
+// matches.
+func Resolve[T Named](target Target, candidates []T) []T {
+	var matches []T
+	seen := 0
+	for _, candidate := range candidates {
+		if !target.Matches(candidate) {
+			continue
+		}
+		if target.All {
+			matches = append(matches, candidate)
+			continue
+		}
+		seen++
+		if seen == target.Ordinal {
+			return []T{candidate}
+		}
+	}
+	return matches
+}
+
+// splitAt splits the words at the first of the prepositions, ie "sword to bob"
+// into sword and bob.
+func splitAt(words []string, prepositions ...string) (before []string, after []string, found bool) {
+	for idx, word := range words {
+		for _, preposition := range prepositions {
+			if strings.EqualFold(word, preposition) {
+				return words[:idx], words[idx+1:], true
+			}
+		}
+	}
+	return words, nil, false
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"mud/items"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"look":                         {"look"},
+		"  take   sword  ":             {"take", "sword"},
+		`take "long sword" from chest`: {"take", "long sword", "from", "chest"},
+		"give 'silver ring' to Bob":    {"give", "silver ring", "to", "Bob"},
+		"say don't go":                 {"say", "don't", "go"},
+		`say "unclosed quote`:          {"say", `"unclosed`, "quote"},
+		"' hello":                      {"'", "hello"},
+		`say ""`:                       {"say", `""`},
+		"":                             {},
+	}
+	for input, expected := range tests {
+		got := Tokenize(input)
+		if strings.Join(got, "|") != strings.Join(expected, "|") || len(got) != len(expected) {
+			t.Errorf("Tokenize(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestSplitCommands(t *testing.T) {
+	got := splitCommands(`say "a;b";look;say don't; north`)
+	expected := []string{`say "a;b"`, "look", "say don't", " north"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("splitCommands() = %q, expected %q", got, expected)
+	}
+}
+
+func TestRestOfLine(t *testing.T) {
+	if got := restOfLine(`tell Bob  Hello, "friend"!`, 2); got != `Hello, "friend"!` {
+		t.Errorf("restOfLine() = %q", got)
+	}
+	if got := restOfLine("tell Bob", 2); got != "" {
+		t.Errorf("restOfLine() past the end = %q", got)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		words    []string
+		expected Target
+	}{
+		{[]string{"sword"}, Target{Keyword: "sword", Ordinal: 1}},
+		{[]string{"2.sword"}, Target{Keyword: "sword", Ordinal: 2}},
+		{[]string{"all"}, Target{All: true}},
+		{[]string{"ALL.sword"}, Target{Keyword: "sword", All: true}},
+		{[]string{"long", "sword"}, Target{Keyword: "long sword", Ordinal: 1}},
+		{[]string{"0.sword"}, Target{Keyword: "0.sword", Ordinal: 1}},
+		{[]string{"mr.smith"}, Target{Keyword: "mr.smith", Ordinal: 1}},
+	}
+	for _, test := range tests {
+		if got := ParseTarget(test.words...); got != test.expected {
+			t.Errorf("ParseTarget(%q) = %+v, expected %+v", test.words, got, test.expected)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	candidates := []*items.Item{
+		{Name: "a rusty sword"},
+		{Name: "a long sword"},
+		{Name: "a wooden shield"},
+		{Name: "the corpse of Reg"},
+	}
+	names := func(found []*items.Item) string {
+		var names []string
+		for _, item := range found {
+			names = append(names, item.Name)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	tests := map[string]string{
+		"sword":       "a rusty sword",
+		"2.sword":     "a long sword",
+		"3.sword":     "",
+		"long sw":     "a long sword",
+		"sword long":  "",
+		"SHIELD":      "a wooden shield",
+		"corpse reg":  "the corpse of Reg",
+		"all.sword":   "a rusty sword, a long sword",
+		"all":         "a rusty sword, a long sword, a wooden shield, the corpse of Reg",
+		"all.nothing": "",
+		"axe":         "",
+	}
+	for typed, expected := range tests {
+		if got := names(Resolve(ParseTarget(strings.Fields(typed)...), candidates)); got != expected {
+			t.Errorf("Resolve(%q) = %q, expected %q", typed, got, expected)
+		}
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	before, after, found := splitAt([]string{"long", "sword", "TO", "bob"}, "to")
+	if !found || strings.Join(before, " ") != "long sword" || strings.Join(after, " ") != "bob" {
+		t.Errorf("splitAt() = %q, %q, %v", before, after, found)
+	}
+	if _, _, found := splitAt([]string{"sword", "bob"}, "to"); found {
+		t.Errorf("splitAt() found a preposition which isn't there")
+	}
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"mud/display"
+	"mud/players"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// helpMenuWidth is as wide as the help menus get before wrapping.
+const helpMenuWidth = 65
+
+// categoryOrder is the order help lists the categories in, any others go after
+// them alphabetically.
+var categoryOrder = []string{
+	CategoryMovement,
+	CategoryCommunication,
+	CategoryInformation,
+	CategoryItems,
+	CategoryCombat,
+	CategorySettings,
+	CategoryAdmin,
+}
+
+// helpTopic is help about something other than a command, ie combat, read
+// from a markdown file.
+type helpTopic struct {
+	Name  string
+	Title string
+	// each paragraph, list item or heading is shown on its own
+	Paragraphs []string
+}
+
+// helpTopics are the topics loaded by LoadHelpTopics, by name.
+var helpTopics = map[string]*helpTopic{}
+
+// LoadHelpTopics reads every .md file in the directory as a help topic named
+// after the file, ie combat.md is help combat.  The first # heading is its
+// title.
+func LoadHelpTopics(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no help topics in %s", dir)
+	}
+
+	topics := make(map[string]*helpTopic, len(paths))
+	for _, path := range paths {
+		topic, err := readHelpTopic(path)
+		if err != nil {
+			return fmt.Errorf("error reading help topic %s: %v", path, err)
+		}
+		topics[topic.Name] = topic
+	}
+	helpTopics = topics
+	return nil
+}
+
+func readHelpTopic(path string) (*helpTopic, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	topic := &helpTopic{Name: name, Title: name}
+
+	var paragraph []string
+	endParagraph := func() {
+		if len(paragraph) > 0 {
+			topic.Paragraphs = append(topic.Paragraphs, strings.Join(paragraph, " "))
+			paragraph = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			endParagraph()
+		case strings.HasPrefix(line, "# ") && topic.Title == name && len(topic.Paragraphs) == 0 && len(paragraph) == 0:
+			topic.Title = plainText(line[2:])
+		case strings.HasPrefix(line, "#"):
+			endParagraph()
+			topic.Paragraphs = append(topic.Paragraphs, strings.ToUpper(plainText(strings.TrimLeft(line, "# "))))
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			endParagraph()
+			paragraph = append(paragraph, "* "+plainText(line[2:]))
+		default:
+			paragraph = append(paragraph, plainText(line))
+		}
+	}
+	endParagraph()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(topic.Paragraphs) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	return topic, nil
+}
+
+// plainText takes out the markdown which doesn't mean anything in a terminal.
+func plainText(line string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(line)
+}
+
+// commandsByCategory groups the commands, each category's sorted by name.
+func commandsByCategory(available map[string]CommandHandlerWithPriority) (map[string][]string, []string) {
+	byCategory := map[string][]string{}
+	for command, handlerWithPriority := range available {
+		byCategory[handlerWithPriority.Category] = append(byCategory[handlerWithPriority.Category], command)
+	}
+
+	var categories []string
+	for _, category := range categoryOrder {
+		if _, ok := byCategory[category]; ok {
+			categories = append(categories, category)
+		}
+	}
+	var others []string
+	for category := range byCategory {
+		if !slices.Contains(categoryOrder, category) {
+			others = append(others, category)
+		}
+	}
+	sort.Strings(others)
+	categories = append(categories, others...)
+
+	for _, commands := range byCategory {
+		sort.Strings(commands)
+	}
+	return byCategory, categories
+}
+
+func printHelpMenu(player *players.Player, title string, elements []string) {
+	lineStyle := "double"
+	display.PrintMenu(player, display.MenuContents{
+		Title:     &title,
+		LineStyle: &lineStyle,
+		MaxWidth:  helpMenuWidth,
+		Elements:  elements,
+	})
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/players"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// HelpCommandHandler shows the commands the player can use and the help
+// topics, or everything about one of them.
+//
+//	help
+//	help <command|topic>
+type HelpCommandHandler struct {
+	Router *CommandRouter
+}
+
+func (h *HelpCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	available := h.Router.availableCommands(player)
+	if len(arguments) == 0 {
+		h.overview(player, available)
+		return
+	}
+
+	word := strings.ToLower(strings.Join(arguments, " "))
+	if name, ok := findCommand(word, available); ok {
+		h.command(player, name, available[name])
+		return
+	}
+	if topic, ok := findTopic(word); ok {
+		printHelpMenu(player, topic.Title, topic.Paragraphs)
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("There's no help on %s, try help on its own for what there is.\n", word), "reset")
+}
+
+// findCommand looks for the command by its whole name first, so that a topic
+// with the same start doesn't lose out to it, then the way the parser would.
+func findCommand(word string, available map[string]CommandHandlerWithPriority) (string, bool) {
+	if _, ok := available[word]; ok {
+		return word, true
+	}
+	if _, ok := helpTopics[word]; ok {
+		return "", false
+	}
+	name := NewCommandParser(word, available).GetCommandName()
+	_, ok := available[name]
+	return name, ok
+}
+
+// findTopic looks for the topic by name, or by the start of it.
+func findTopic(word string) (*helpTopic, bool) {
+	if topic, ok := helpTopics[word]; ok {
+		return topic, true
+	}
+	var names []string
+	for name := range helpTopics {
+		if strings.HasPrefix(name, word) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	sort.Strings(names)
+	return helpTopics[names[0]], true
+}
+
+func (h *HelpCommandHandler) overview(player *players.Player, available map[string]CommandHandlerWithPriority) {
+	byCategory, categories := commandsByCategory(available)
+
+	var elements []string
+	for _, category := range categories {
+		elements = append(elements, fmt.Sprintf("%s: %s", category, strings.Join(byCategory[category], ", ")))
+	}
+	if len(helpTopics) > 0 {
+		var topics []string
+		for name := range helpTopics {
+			topics = append(topics, name)
+		}
+		sort.Strings(topics)
+		elements = append(elements, fmt.Sprintf("Topics: %s", strings.Join(topics, ", ")))
+	}
+	elements = append(elements, "Type help <command> or help <topic> for more, or commands for what each command does.")
+	printHelpMenu(player, "Help", elements)
+}
+
+func (h *HelpCommandHandler) command(player *players.Player, name string, handlerWithPriority CommandHandlerWithPriority) {
+	elements := []string{
+		fmt.Sprintf("Usage: %s", handlerWithPriority.Syntax),
+		handlerWithPriority.Description,
+	}
+	if role := handlerWithPriority.Role; role != "" && role != players.RolePlayer {
+		elements = append(elements, fmt.Sprintf("Needs the %s role.", role))
+	}
+	printHelpMenu(player, name, elements)
+}
+
+func (h *HelpCommandHandler) SetRouter(router *CommandRouter) {
+	h.Router = router
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEveryCommandHasHelp(t *testing.T) {
+	for command, handlerWithPriority := range CommandHandlers {
+		if handlerWithPriority.Syntax == "" || handlerWithPriority.Summary == "" || handlerWithPriority.Description == "" {
+			t.Errorf("%s needs a syntax, summary and description for help", command)
+		}
+		if handlerWithPriority.Category == "" {
+			t.Errorf("%s needs a category for help", command)
+		}
+		if strings.Contains(handlerWithPriority.Summary, "\n") || strings.Contains(handlerWithPriority.Syntax, "\n") {
+			t.Errorf("%s's syntax and summary should be one line each", command)
+		}
+	}
+}
+
+func TestReadHelpTopic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Fishing.md")
+	err := os.WriteFile(path, []byte("# Going **Fishing**\n\nCast your line\nand wait.\n\n## Bait\n- worms\n- `bread`\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topic, err := readHelpTopic(path)
+	if err != nil {
+		t.Fatalf("readHelpTopic() returned %v", err)
+	}
+	if topic.Name != "fishing" || topic.Title != "Going Fishing" {
+		t.Errorf("expected the topic fishing titled Going Fishing, got %q titled %q", topic.Name, topic.Title)
+	}
+	expected := []string{"Cast your line and wait.", "BAIT", "* worms", "* bread"}
+	if strings.Join(topic.Paragraphs, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected the paragraphs %q, got %q", expected, topic.Paragraphs)
+	}
+}
+
+func TestHelpTopics(t *testing.T) {
+	original := helpTopics
+	t.Cleanup(func() { helpTopics = original })
+
+	err := LoadHelpTopics(filepath.Join("..", "help"))
+	if err != nil {
+		t.Fatalf("LoadHelpTopics() returned %v", err)
+	}
+	for name := range helpTopics {
+		if _, ok := CommandHandlers[name]; ok {
+			t.Errorf("the help topic %s has the same name as a command", name)
+		}
+	}
+
+	if topic, ok := findTopic("comb"); !ok || topic.Name != "combat" {
+		t.Errorf("expected the start of a topic's name to find it")
+	}
+	if name, ok := findCommand("inv", CommandHandlers); !ok || name != "inventory" {
+		t.Errorf("expected the start of a command's name to find it, got %q", name)
+	}
+	helpTopics["inv"] = &helpTopic{Name: "inv"}
+	if _, ok := findCommand("inv", CommandHandlers); ok {
+		t.Errorf("expected a topic's whole name to beat the start of a command's")
+	}
+}
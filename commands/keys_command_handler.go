@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/players"
+	"mud/transport"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// KeysCommandHandler manages the ssh keys which log the player straight in.
+//
+//	keys list
+//	keys add [<public key>]  - without a key, adds the one you connected with
+//	keys remove <number|fingerprint>
+type KeysCommandHandler struct{}
+
+func (h *KeysCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) == 0 {
+		h.list(db, player)
+		return
+	}
+
+	switch strings.ToLower(arguments[0]) {
+	case "list":
+		h.list(db, player)
+	case "add":
+		h.add(db, player, command, arguments[1:])
+	case "remove":
+		h.remove(db, player, arguments[1:])
+	default:
+		display.PrintWithColor(player, "Usage: keys [list|add [<public key>]|remove <number>]\n", "reset")
+	}
+}
+
+func (h *KeysCommandHandler) list(db *sqlx.DB, player *players.Player) {
+	keys, err := players.GetPlayerKeys(db, player.UUID)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Error retrieving keys: %v\n", err), "danger")
+		return
+	}
+	if len(keys) == 0 {
+		display.PrintWithColor(player, "You haven't added any keys.\n", "reset")
+		return
+	}
+
+	display.PrintWithColor(player, "Your keys:\n", "reset")
+	for idx, key := range keys {
+		display.PrintWithColor(player, fmt.Sprintf("%d. %s (added %s)\n", idx+1, key.Fingerprint, key.CreatedAt.Format("2006-01-02")), "primary")
+	}
+}
+
+func (h *KeysCommandHandler) add(db *sqlx.DB, player *players.Player, command string, arguments []string) {
+	var authorizedKey string
+	if len(arguments) > 0 {
+		// keys are case sensitive, so take them from the command as typed
+		authorizedKey = strings.TrimSpace(command[strings.Index(strings.ToLower(command), "add")+len("add"):])
+	} else if keyed, ok := player.Session.(transport.KeyAuthenticated); ok && keyed.KeyFingerprint() != "" {
+		// the key they connected with, which is already on one of their characters
+		var err error
+		authorizedKey, err = players.GetPublicKey(db, keyed.KeyFingerprint())
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Couldn't add that key: %v\n", err), "danger")
+			return
+		}
+	}
+	if authorizedKey == "" {
+		display.PrintWithColor(player, "Add which key?  Paste the public key, ie the contents of ~/.ssh/id_ed25519.pub\n", "reset")
+		return
+	}
+
+	key, err := players.AddPlayerKey(db, player.UUID, authorizedKey)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Couldn't add that key: %v\n", err), "danger")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("Added key %s.  Connecting with it will log you straight in.\n", key.Fingerprint), "reset")
+}
+
+func (h *KeysCommandHandler) remove(db *sqlx.DB, player *players.Player, arguments []string) {
+	if len(arguments) == 0 {
+		display.PrintWithColor(player, "Remove which key?\n", "reset")
+		return
+	}
+
+	fingerprint := arguments[0]
+	if idx, err := strconv.Atoi(arguments[0]); err == nil {
+		keys, err := players.GetPlayerKeys(db, player.UUID)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Error retrieving keys: %v\n", err), "danger")
+			return
+		}
+		if idx < 1 || idx > len(keys) {
+			display.PrintWithColor(player, "You don't have a key with that number.\n", "reset")
+			return
+		}
+		fingerprint = keys[idx-1].Fingerprint
+	}
+
+	if err := players.RemovePlayerKey(db, player.UUID, fingerprint); err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Couldn't remove that key: %v\n", err), "danger")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("Removed key %s.\n", fingerprint), "reset")
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/notifications"
+	"mud/players"
+	"mud/world_state"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type KillCommandHandler struct {
+	Notifier   *notifications.Notifier
+	WorldState *world_state.WorldState
+}
+
+func (h *KillCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) == 0 {
+		display.PrintWithColor(player, "Kill what?\n", "reset")
+		return
+	}
+
+	if player.HP <= 0 {
+		display.PrintWithColor(player, "You are in no condition to fight.\n", "warning")
+		return
+	}
+
+	currentRoom := h.WorldState.GetRoom(player.RoomUUID, false)
+	target := ParseTarget(arguments...)
+	found := Resolve(target, currentRoom.GetMobs())
+	if len(found) == 0 || target.All {
+		display.PrintWithColor(player, "You don't see that here.\n", "reset")
+		return
+	}
+	mob := found[0]
+
+	currentRoom.Engage(player, mob)
+
+	display.PrintWithColor(player, fmt.Sprintf("You attack %s!\n", mob.Name), "danger")
+	h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s attacks %s!\n", player.Name, mob.Name))
+}
+
+func (h *KillCommandHandler) SetNotifier(notifier *notifications.Notifier) {
+	h.Notifier = notifier
+}
+
+func (h *KillCommandHandler) SetWorldState(world_state *world_state.WorldState) {
+	h.WorldState = world_state
+}
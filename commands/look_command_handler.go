@@ -22,30 +22,31 @@ func (h *LookCommandHandler) SetWorldState(world_state *world_state.WorldState)
 func (h *LookCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
 	currentRoomUUID := player.RoomUUID
 	currentRoom := h.WorldState.GetRoom(currentRoomUUID, false)
+	itemsInRoom, mobsInRoom, playersInRoom := currentRoom.GetItems(), currentRoom.GetMobs(), currentRoom.GetPlayers()
 
 	if len(arguments) == 0 {
 		display.PrintWithColor(player, fmt.Sprintf("%s\n", currentRoom.Name), "primary")
 		display.PrintWithColor(player, fmt.Sprintf("%s\n", currentRoom.Description), "secondary")
 		display.PrintWithColor(player, "-----------------------\n\n", "secondary")
 
-		if len(currentRoom.Items) > 0 {
+		if len(itemsInRoom) > 0 {
 			display.PrintWithColor(player, "You see the following items:\n", "reset")
-			for _, item := range currentRoom.Items {
+			for _, item := range itemsInRoom {
 				display.PrintWithColor(player, fmt.Sprintf("%s\n", item.Name), "primary")
 			}
 			display.PrintWithColor(player, "\n", "reset")
 		}
 
-		if len(currentRoom.Mobs) > 0 {
-			for _, mob := range currentRoom.Mobs {
+		if len(mobsInRoom) > 0 {
+			for _, mob := range mobsInRoom {
 				display.PrintWithColor(player, fmt.Sprintf("%s\n", mob.Name), "warning")
 			}
 
 		}
 
-		if len(currentRoom.Players) > 1 {
+		if len(playersInRoom) > 1 {
 			display.PrintWithColor(player, "You see the following players:\n", "reset")
-			for _, playerInRoom := range currentRoom.Players {
+			for _, playerInRoom := range playersInRoom {
 				if player.UUID != playerInRoom.UUID {
 					display.PrintWithColor(player, fmt.Sprintf("%s\n", playerInRoom.Name), "primary")
 				}
@@ -55,66 +56,78 @@ func (h *LookCommandHandler) Execute(db *sqlx.DB, player *players.Player, comman
 
 		exitsHandler := &ExitsCommandHandler{ShowOnlyDirections: true, WorldState: h.WorldState}
 		exitsHandler.Execute(db, player, "exits", arguments, currentChannel, updateChannel)
-	} else if len(arguments) == 1 {
-		exits := currentRoom.Exits
-		exitMap := map[string]*areas.Room{
-			"North": exits.North,
-			"South": exits.South,
-			"West":  exits.West,
-			"East":  exits.East,
-			"Up":    exits.Up,
-			"Down":  exits.Down,
-		}
+		return
+	}
+
+	// look at <target>, look in <container>
+	words := arguments
+	if len(words) > 1 && (strings.EqualFold(words[0], "at") || strings.EqualFold(words[0], "in")) {
+		words = words[1:]
+	}
+
+	if len(words) == 1 && h.lookDirection(player, currentRoom, words[0]) {
+		return
+	}
 
-		lookDirection := arguments[0]
-		directionMatch := false
-
-		for direction, exit := range exitMap {
-			if lookDirection == direction {
-				directionMatch = true
-				if exit != nil {
-					exitRoom := h.WorldState.GetRoom(exit.UUID, false)
-					display.PrintWithColor(player, fmt.Sprintf("You look %s.  You see %s\n", direction, exitRoom.Name), "reset")
-				} else {
-					display.PrintWithColor(player, "You don't see anything in that direction\n", "reset")
+	target := ParseTarget(words...)
+	if target.All {
+		display.PrintWithColor(player, "You can only look at one thing at a time.\n", "reset")
+		return
+	}
+
+	visibleItems := append(itemsInRoom, player.Inventory...)
+	if found := Resolve(target, visibleItems); len(found) > 0 {
+		item := found[0]
+		display.PrintWithColor(player, fmt.Sprintf("%s\n", item.Name), "reset")
+		if item.Container {
+			if len(item.Contents) == 0 {
+				display.PrintWithColor(player, "It is empty.\n", "reset")
+			} else {
+				display.PrintWithColor(player, "It contains:\n", "reset")
+				for _, content := range item.Contents {
+					display.PrintWithColor(player, fmt.Sprintf("%s\n", content.Name), "primary")
 				}
 			}
 		}
+		return
+	}
 
-		if !directionMatch {
-			target := arguments[0]
-			found := false
+	if found := Resolve(target, mobsInRoom); len(found) > 0 {
+		display.PrintWithColor(player, fmt.Sprintf("You see %s.\n", found[0].Name), "danger")
+		return
+	}
 
-			items := append(currentRoom.Items, player.Inventory...)
-			for _, item := range items {
-				if item.Name == target {
-					display.PrintWithColor(player, fmt.Sprintf("%s\n", item.Name), "reset")
-					found = true
-					break
-				}
-			}
+	if found := Resolve(target, playersInRoom); len(found) > 0 {
+		display.PrintWithColor(player, fmt.Sprintf("You see %s.\n", found[0].Name), "reset")
+		return
+	}
 
-			for _, playerInRoom := range currentRoom.Players {
-				if strings.ToLower(playerInRoom.Name) == target {
-					display.PrintWithColor(player, fmt.Sprintf("You see %s.\n", playerInRoom.Name), "reset")
-					found = true
-					break
-				}
-			}
+	display.PrintWithColor(player, "You don't see that.\n", "reset")
+}
 
-			for _, mobInRoom := range currentRoom.Mobs {
-				if strings.ToLower(mobInRoom.Name) == target {
-					display.PrintWithColor(player, fmt.Sprintf("You see %s.\n", mobInRoom.Name), "danger")
-					found = true
-					break
-				}
-			}
+// lookDirection shows what's through the exit, if the word is a direction.
+func (h *LookCommandHandler) lookDirection(player *players.Player, currentRoom *areas.Room, word string) bool {
+	exits := currentRoom.Exits
+	exitMap := map[string]*areas.Room{
+		"North": exits.North,
+		"South": exits.South,
+		"West":  exits.West,
+		"East":  exits.East,
+		"Up":    exits.Up,
+		"Down":  exits.Down,
+	}
 
-			if !found {
-				display.PrintWithColor(player, "You don't see that.\n", "reset")
-			}
+	for direction, exit := range exitMap {
+		if !strings.EqualFold(word, direction) {
+			continue
+		}
+		if exit != nil {
+			exitRoom := h.WorldState.GetRoom(exit.UUID, false)
+			display.PrintWithColor(player, fmt.Sprintf("You look %s.  You see %s\n", direction, exitRoom.Name), "reset")
+		} else {
+			display.PrintWithColor(player, "You don't see anything in that direction\n", "reset")
 		}
-	} else {
-		display.PrintWithColor(player, "I don't know how to do that yet.\n", "reset")
+		return true
 	}
+	return false
 }
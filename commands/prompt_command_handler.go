@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/players"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PromptCommandHandler changes what the player's prompt shows, see
+// players.PromptTokens.
+//
+//	prompt
+//	prompt <template>
+//	prompt default
+type PromptCommandHandler struct{}
+
+func (h *PromptCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) == 0 {
+		h.show(player)
+		return
+	}
+
+	// the template is taken as typed, quotes keep the spaces at its ends
+	template := restOfLine(command, 1)
+	if len(arguments) == 1 && strings.HasPrefix(template, `"`) {
+		template = arguments[0]
+	}
+	if strings.EqualFold(template, "default") {
+		template = ""
+	}
+
+	err := player.SetPrompt(db, template)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Your prompt wasn't changed, %v.\n", err), "danger")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("Your prompt now looks like this: %s\n", player.RenderPrompt()), "reset")
+}
+
+func (h *PromptCommandHandler) show(player *players.Player) {
+	template := player.Prompt
+	if template == "" {
+		template = players.DefaultPrompt + " (the default)"
+	}
+
+	elements := []string{fmt.Sprintf("Your prompt: %s", template)}
+	for _, token := range players.PromptTokens {
+		elements = append(elements, fmt.Sprintf("%s - %s", token.Token, token.Description))
+	}
+	elements = append(elements, `Put quotes around a prompt which ends in a space, ie prompt "<%h/%Hhp %e> "`)
+	printHelpMenu(player, "Prompt", elements)
+}
@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"mud/areas"
+	"mud/players"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// QueuedCommandHandler runs a regular command handler from the area's action
+// queue, so that it happens on the beat instead of as soon as it is typed.
+type QueuedCommandHandler struct {
+	Handler CommandHandler
+}
+
+func (h *QueuedCommandHandler) Execute(db *sqlx.DB, player *players.Player, action areas.Action, updateChannel func(string)) {
+	h.Handler.Execute(db, player, action.GetCommand(), action.GetArguments(), nil, updateChannel)
+}
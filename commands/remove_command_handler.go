@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"mud/areas"
 	"mud/display"
 	"mud/notifications"
@@ -23,6 +24,19 @@ func (h *RemoveCommandHandler) Execute(db *sqlx.DB, player *players.Player, comm
 		return
 	}
 
-	player.Remove(db, arguments[0])
+	found := Resolve(ParseTarget(arguments...), player.Equipment.Items())
+	if len(found) == 0 {
+		display.PrintWithColor(player, "You aren't wearing that.\n", "reset")
+		return
+	}
 
+	for _, equipped := range found {
+		err := player.Remove(db, equipped)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Error removing %s: %v\n", equipped.GetName(), err), "danger")
+			continue
+		}
+		display.PrintWithColor(player, fmt.Sprintf("You remove %s.\n", equipped.GetName()), "reset")
+		h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s removes %s.\n", player.Name, equipped.GetName()))
+	}
 }
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"mud/areas"
+	"mud/audit"
 	"mud/display"
 	"mud/notifications"
 	"mud/players"
@@ -19,12 +20,21 @@ type CommandRouterInterface interface {
 
 type CommandRouter struct {
 	Handlers map[string]CommandHandler
-	mu       sync.RWMutex
+	Lags     map[string]int
+	Roles    map[string]players.Role
+	// closed once the areas have stopped running, after which nothing can be
+	// queued on them
+	AreasDone <-chan struct{}
+	// where each area's queued commands go, by area uuid
+	AreaChannels map[string]chan areas.Action
+	mu           sync.RWMutex
 }
 
 func NewCommandRouter() *CommandRouter {
 	return &CommandRouter{
 		Handlers: make(map[string]CommandHandler),
+		Lags:     make(map[string]int),
+		Roles:    make(map[string]players.Role),
 		mu:       sync.RWMutex{},
 	}
 }
@@ -36,6 +46,26 @@ func (r *CommandRouter) RegisterHandler(command string, handler CommandHandler)
 	r.Handlers[command] = handler
 }
 
+// RegisterRole restricts a command to players with the role.
+func (r *CommandRouter) RegisterRole(command string, role players.Role) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Roles[command] = role
+}
+
+// RegisterLaggedHandler registers a command which gets queued up in the area
+// instead of running right away.  Without lag it runs as soon as the area gets
+// to it.
+func (r *CommandRouter) RegisterLaggedHandler(command string, handler CommandHandler, lag int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Handlers[command] = handler
+	r.Lags[command] = lag
+	areas.RegisterActionHandler(command, &QueuedCommandHandler{Handler: handler})
+}
+
 func RegisterCommands(router *CommandRouter, notifier *notifications.Notifier, worldState *world_state.WorldState, commands map[string]CommandHandlerWithPriority) {
 	for command, handlerWithPriority := range commands {
 		if notifiable, ok := handlerWithPriority.Handler.(Notifiable); ok {
@@ -44,18 +74,74 @@ func RegisterCommands(router *CommandRouter, notifier *notifications.Notifier, w
 		if worldStateable, ok := handlerWithPriority.Handler.(UsesWorldState); ok {
 			worldStateable.SetWorldState(worldState)
 		}
+		if routable, ok := handlerWithPriority.Handler.(UsesRouter); ok {
+			routable.SetRouter(router)
+		}
+		if handlerWithPriority.Role != "" {
+			router.RegisterRole(command, handlerWithPriority.Role)
+		}
+		if handlerWithPriority.Lag > 0 || handlerWithPriority.Queued {
+			router.RegisterLaggedHandler(command, handlerWithPriority.Handler, handlerWithPriority.Lag)
+			continue
+		}
 		router.RegisterHandler(command, handlerWithPriority.Handler)
 	}
+
+	areas.RegisterActionHandler(teleportAction, &TeleportActionHandler{Notifier: notifier, WorldState: worldState})
+	areas.RegisterActionHandler(forcedAction, &ForcedActionHandler{Router: router})
+}
+
+// availableCommands leaves out the commands the player doesn't have the role
+// for, so they can't be found by typing the start of them either.
+func (r *CommandRouter) availableCommands(player *players.Player) map[string]CommandHandlerWithPriority {
+	available := make(map[string]CommandHandlerWithPriority, len(CommandHandlers))
+	for command, handlerWithPriority := range CommandHandlers {
+		if player.HasRole(r.Roles[command]) {
+			available[command] = handlerWithPriority
+		}
+	}
+	return available
+}
+
+// isAliasDefinition checks whether the line is the alias command, rather than
+// one of the player's aliases.
+func (r *CommandRouter) isAliasDefinition(player *players.Player, line string, availableCommands map[string]CommandHandlerWithPriority) bool {
+	if _, ok := aliasName(player.Aliases, line); ok {
+		return false
+	}
+	return NewCommandParser(line, availableCommands).GetCommandName() == "alias"
 }
 
 func (r *CommandRouter) HandleCommand(db *sqlx.DB, player *players.Player, command []byte, currentChannel chan areas.Action, updateChannel func(string)) {
 	// Convert the command []byte to a string and trim the extra characters off.
-	commandString := strings.ToLower(strings.TrimSpace(string(command)))
+	commandString := strings.TrimSpace(string(command))
+
+	r.mu.RLock()
+	availableCommands := r.availableCommands(player)
+	r.mu.RUnlock()
+
+	commandBlocks := splitCommands(commandString)
+	if r.isAliasDefinition(player, commandString, availableCommands) {
+		// the ; are part of what the alias will run
+		commandBlocks = []string{commandString}
+	} else if len(player.Aliases) > 0 {
+		var err error
+		commandBlocks, err = expandAliases(player.Aliases, commandBlocks)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Your alias wasn't run, %v.\n", err), "danger")
+			return
+		}
+	}
 
-	commandBlocks := strings.Split(commandString, ";")
 	for _, command := range commandBlocks {
-		// Parse the command string.
-		commandParser := NewCommandParser(strings.TrimSpace(command), CommandHandlers)
+		command = expandShortcuts(strings.TrimSpace(command))
+		if command == "" {
+			continue
+		}
+
+		// Parse the command string.  Handlers get the command as it was typed,
+		// for the ones which pass on what the player wrote, ie say.
+		commandParser := NewCommandParser(command, availableCommands)
 
 		// Get the command name and arguments.
 		commandName := commandParser.GetCommandName()
@@ -63,14 +149,46 @@ func (r *CommandRouter) HandleCommand(db *sqlx.DB, player *players.Player, comma
 
 		// Check if the command is registered.
 		r.mu.RLock()
-		defer r.mu.RUnlock()
-
 		handler, ok := r.Handlers[commandName]
-		if !ok {
+		role := r.Roles[commandName]
+		lag, queued := r.Lags[commandName]
+		r.mu.RUnlock()
+
+		if !ok || !player.HasRole(role) {
 			display.PrintWithColor(player, fmt.Sprintf("Unknown command: %s\n", command), "danger")
 			return
 		}
 
+		// anything which needs more than being a player gets written down
+		if role != "" && role != players.RolePlayer {
+			err := audit.Record(db, player.AccountUUID, player.UUID, player.Name, strings.TrimSpace(command))
+			if err != nil {
+				fmt.Printf("error auditing %s's %s: %v\n", player.Name, commandName, err)
+			}
+		}
+
+		if queued && currentChannel != nil {
+			action := areas.Action{Player: player, Command: commandName, Arguments: arguments, Lag: lag, UpdateChannel: updateChannel}
+			if lag == 0 {
+				// wait for it, so that what it prints comes before the prompt
+				action.Done = make(chan struct{})
+			}
+			select {
+			case currentChannel <- action:
+			case <-r.AreasDone:
+				display.PrintWithColor(player, fmt.Sprintf("The world has stopped, %s wasn't done.\n", commandName), "danger")
+				return
+			}
+			if action.Done != nil {
+				select {
+				case <-action.Done:
+				case <-r.AreasDone:
+					return
+				}
+			}
+			continue
+		}
+
 		handler.Execute(db, player, command, arguments, currentChannel, updateChannel)
 	}
 }
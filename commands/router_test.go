@@ -0,0 +1,267 @@
+package commands_test
+
+import (
+	"bytes"
+	"mud/areas"
+	"mud/audit"
+	"mud/commands"
+	"mud/items"
+	"mud/mobs"
+	"mud/notifications"
+	"mud/players"
+	"mud/sessions"
+	"mud/transport"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type fakeSession struct {
+	transport.Conn
+	out bytes.Buffer
+}
+
+func (s *fakeSession) Write(p []byte) (int, error) {
+	return s.out.Write(p)
+}
+
+type recordingHandler struct {
+	ran       bool
+	commands  []string
+	arguments [][]string
+}
+
+func (h *recordingHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	h.ran = true
+	h.commands = append(h.commands, command)
+	h.arguments = append(h.arguments, arguments)
+}
+
+func TestRoleRestrictedCommands(t *testing.T) {
+	handler := &recordingHandler{}
+	original := commands.CommandHandlers["/sethealth"]
+	commands.CommandHandlers["/sethealth"] = commands.CommandHandlerWithPriority{Handler: handler, Priority: 10, Role: players.RoleAdmin}
+	defer func() { commands.CommandHandlers["/sethealth"] = original }()
+
+	router := commands.NewCommandRouter()
+	commands.RegisterCommands(router, nil, nil, map[string]commands.CommandHandlerWithPriority{
+		"/sethealth": commands.CommandHandlers["/sethealth"],
+	})
+
+	for _, typed := range []string{"/sethealth bob 10", "/seth bob 10"} {
+		session := &fakeSession{}
+		player := players.NewPlayer(session)
+		player.Roles = []players.Role{players.RolePlayer, players.RoleBuilder}
+
+		router.HandleCommand(nil, player, []byte(typed), nil, nil)
+		if handler.ran {
+			t.Fatalf("%q ran for a player without the admin role", typed)
+		}
+		if !strings.Contains(session.out.String(), "Unknown command") {
+			t.Errorf("%q should look like an unknown command, got %q", typed, session.out.String())
+		}
+	}
+
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.MustExec(`CREATE TABLE admin_audit (id INTEGER PRIMARY KEY AUTOINCREMENT, account_uuid VARCHAR(36), player_uuid VARCHAR(36), player_name TEXT, command TEXT, created_at DATETIME)`)
+
+	admin := players.NewPlayer(&fakeSession{})
+	admin.Name = "Root"
+	admin.Roles = []players.Role{players.RoleAdmin}
+	router.HandleCommand(db, admin, []byte("/seth Bob 10"), nil, nil)
+	if !handler.ran {
+		t.Errorf("/seth should have run /sethealth for an admin")
+	}
+
+	entries, err := audit.Recent(db, "root", 10)
+	if err != nil {
+		t.Fatalf("error reading the audit log: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "/seth Bob 10" {
+		t.Errorf("expected the admin command to be audited as typed, got %v", entries)
+	}
+}
+
+func TestParseRoles(t *testing.T) {
+	roles := players.ParseRoles("player, Moderator,wizard")
+	if len(roles) != 2 || roles[0] != players.RolePlayer || roles[1] != players.RoleModerator {
+		t.Errorf("unexpected roles %v", roles)
+	}
+
+	moderator := players.Player{Roles: roles}
+	if !moderator.HasRole(players.RoleModerator) || moderator.HasRole(players.RoleBuilder) {
+		t.Errorf("moderator has the wrong roles")
+	}
+	if !(&players.Player{Roles: []players.Role{players.RoleAdmin}}).HasRole(players.RoleBuilder) {
+		t.Errorf("admins should have every role")
+	}
+}
+
+func TestCommandsKeepWhatWasTyped(t *testing.T) {
+	handler := &recordingHandler{}
+	router := commands.NewCommandRouter()
+	router.RegisterHandler("say", handler)
+	router.RegisterHandler("'", handler)
+
+	player := players.NewPlayer(&fakeSession{})
+	router.HandleCommand(nil, player, []byte(`SAY "Hello; There" friend;'Bye now;;`), nil, nil)
+
+	expectedCommands := []string{`SAY "Hello; There" friend`, "' Bye now"}
+	if strings.Join(handler.commands, "|") != strings.Join(expectedCommands, "|") {
+		t.Fatalf("expected the commands %q, got %q", expectedCommands, handler.commands)
+	}
+	if strings.Join(handler.arguments[0], "|") != "Hello; There|friend" {
+		t.Errorf("expected the quoted words to stay together with their case, got %q", handler.arguments[0])
+	}
+}
+
+func TestAliasesExpandBeforeParsing(t *testing.T) {
+	handler := &recordingHandler{}
+	router := commands.NewCommandRouter()
+	router.RegisterHandler("kill", handler)
+	router.RegisterHandler("look", handler)
+	router.RegisterHandler("alias", handler)
+
+	player := players.NewPlayer(&fakeSession{})
+	player.Aliases = map[string]string{"kl": "kill $1;look"}
+	router.HandleCommand(nil, player, []byte("kl goblin"), nil, nil)
+
+	expectedCommands := []string{"kill goblin", "look"}
+	if strings.Join(handler.commands, "|") != strings.Join(expectedCommands, "|") {
+		t.Fatalf("expected the commands %q, got %q", expectedCommands, handler.commands)
+	}
+
+	// defining an alias keeps its ; for when it's run
+	handler.commands = nil
+	router.HandleCommand(nil, player, []byte("alias lk look;kill $1"), nil, nil)
+	if len(handler.commands) != 1 || handler.commands[0] != "alias lk look;kill $1" {
+		t.Errorf("expected the alias definition to be one command, got %q", handler.commands)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	router := commands.NewCommandRouter()
+	router.RegisterHandler("look", &recordingHandler{})
+	router.RegisterHandler("logout", &recordingHandler{})
+	router.RegisterHandler("/goto", &recordingHandler{})
+	router.RegisterRole("/goto", players.RoleBuilder)
+
+	player := players.NewPlayer(&fakeSession{})
+	player.UUID = "me"
+	room := &areas.Room{
+		Players: []*players.Player{player, {UUID: "bob", Name: "Bob"}},
+		Mobs:    []*mobs.Mob{{Name: "a goblin warrior"}},
+		Items:   []*items.Item{{Name: "the Golden Key"}},
+	}
+
+	// commands the player doesn't have the role for aren't suggested
+	completions := map[string]string{
+		"":         "logout|look",
+		"lo":       "logout|look",
+		"look ":    "bob|goblin|golden|key|warrior",
+		"look gol": "bob|goblin|golden|key|warrior",
+	}
+	for line, expected := range completions {
+		got := router.Complete(player, room, line)
+		sort.Strings(got)
+		if strings.Join(got, "|") != expected {
+			t.Errorf("Complete(%q) = %q, expected %q", line, got, expected)
+		}
+	}
+}
+
+func TestLaggedCommandsAfterTheAreasStop(t *testing.T) {
+	handler := &recordingHandler{}
+	router := commands.NewCommandRouter()
+	router.RegisterLaggedHandler("kill", handler, 2)
+	done := make(chan struct{})
+	close(done)
+	router.AreasDone = done
+
+	session := &fakeSession{}
+	player := players.NewPlayer(session)
+	// nothing is reading from the area's channel any more
+	returned := make(chan struct{})
+	go func() {
+		router.HandleCommand(nil, player, []byte("kill goblin;kill orc"), make(chan areas.Action), nil)
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("expected HandleCommand to give up once the areas have stopped")
+	}
+	if handler.ran || !strings.Contains(session.out.String(), "wasn't done") {
+		t.Errorf("expected the player to be told kill wasn't done, got %q", session.out.String())
+	}
+}
+
+func TestQueuedCommandsWithoutLagWaitForTheArea(t *testing.T) {
+	handler := &recordingHandler{}
+	router := commands.NewCommandRouter()
+	router.RegisterLaggedHandler("look", handler, 0)
+	router.AreasDone = make(chan struct{})
+
+	player := players.NewPlayer(&fakeSession{})
+	area := make(chan areas.Action)
+	returned := make(chan struct{})
+	go func() {
+		router.HandleCommand(nil, player, []byte("look"), area, nil)
+		close(returned)
+	}()
+
+	action := <-area
+	if action.Command != "look" || action.Done == nil {
+		t.Fatalf("expected look to be queued for the area to finish, got %+v", action)
+	}
+	select {
+	case <-returned:
+		t.Fatal("expected HandleCommand to wait until the area has run look")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(action.Done)
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("expected HandleCommand to return once the area had run look")
+	}
+	if handler.ran {
+		t.Error("expected look to be run by the area, not the router")
+	}
+}
+
+func TestForcedCommandsAreQueuedOnTheTargetsArea(t *testing.T) {
+	registry := sessions.NewRegistry()
+	admin := players.NewPlayer(&fakeSession{})
+	admin.UUID, admin.Name = "alice", "Alice"
+	target := players.NewPlayer(&fakeSession{})
+	target.UUID, target.Name = "bob", "Bob"
+	registry.Add(admin)
+	registry.Add(target)
+	registry.Move(target.UUID, "sewers", "cellar")
+
+	router := commands.NewCommandRouter()
+	sewers := make(chan areas.Action, 1)
+	router.AreaChannels = map[string]chan areas.Action{"sewers": sewers}
+	handler := &commands.ForceCommandHandler{Notifier: notifications.NewNotifier(registry), Router: router}
+	handler.Execute(nil, admin, "/force bob say hi there", []string{"bob", "say", "hi", "there"}, make(chan areas.Action), nil)
+
+	select {
+	case action := <-sewers:
+		if action.Player != target || action.Command != "forced" || strings.Join(action.Arguments, "|") != "say hi there" {
+			t.Errorf("expected Bob to be forced to say hi there, got %+v", action)
+		}
+		if action.Done != nil {
+			t.Error("expected the admin not to wait on Bob's area")
+		}
+	default:
+		t.Fatal("expected the forced command to be queued on Bob's area")
+	}
+}
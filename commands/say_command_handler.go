@@ -6,7 +6,6 @@ import (
 	"mud/display"
 	"mud/notifications"
 	"mud/players"
-	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -16,7 +15,8 @@ type SayHandler struct {
 }
 
 func (h *SayHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
-	msg := strings.Join(arguments, " ")
+	// what was typed rather than the arguments, which lose their quotes
+	msg := restOfLine(command, 1)
 	display.PrintWithColor(player, fmt.Sprintf("You say \"%s\"\n", msg), "reset")
 	h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s says \"%s\"\n", player.Name, msg))
 }
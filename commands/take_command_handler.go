@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"mud/areas"
 	"mud/display"
+	"mud/items"
 	"mud/notifications"
 	"mud/players"
 	"mud/world_state"
@@ -21,26 +22,81 @@ func (h *TakeCommandHandler) SetWorldState(world_state *world_state.WorldState)
 }
 
 func (h *TakeCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) == 0 {
+		display.PrintWithColor(player, "Take what?\n", "reset")
+		return
+	}
+
 	roomUUID := player.RoomUUID
 	currentRoom := h.WorldState.GetRoom(roomUUID, false)
-	items := currentRoom.Items
-
-	if len(items) > 0 {
-		for _, item := range items {
-			if item.GetName() == arguments[0] {
-				if err := currentRoom.RemoveItem(item); err != nil {
-					display.PrintWithColor(player, fmt.Sprintf("error removing item from room: %v", err), "reset")
-					break
-				}
-				player.AddItem(db, item)
-
-				display.PrintWithColor(player, fmt.Sprintf("You take the %s.\n", item.GetName()), "reset")
-				h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s takes %s.\n", player.Name, item.Name))
-				break
+
+	// take <item> from <container>
+	itemWords, containerWords, fromContainer := splitAt(arguments, "from")
+	if fromContainer {
+		h.takeFromContainer(db, player, currentRoom, ParseTarget(itemWords...), ParseTarget(containerWords...))
+		return
+	}
+
+	target := ParseTarget(arguments...)
+	found := Resolve(target, currentRoom.GetItems())
+	// take <item> <container>, from before there was from
+	if len(found) == 0 && len(arguments) > 1 {
+		h.takeFromContainer(db, player, currentRoom, ParseTarget(arguments[:len(arguments)-1]...), ParseTarget(arguments[len(arguments)-1]))
+		return
+	}
+	if len(found) == 0 {
+		display.PrintWithColor(player, "You don't see that here.\n", "reset")
+		return
+	}
+
+	for _, item := range found {
+		if item.Container {
+			// taking everything leaves the corpses and chests where they are
+			if !target.All {
+				display.PrintWithColor(player, fmt.Sprintf("You can't take %s.\n", item.GetName()), "reset")
 			}
+			continue
+		}
+		if err := currentRoom.RemoveItem(item); err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("error removing item from room: %v", err), "reset")
+			continue
+		}
+		player.AddItem(db, item)
+
+		display.PrintWithColor(player, fmt.Sprintf("You take the %s.\n", item.GetName()), "reset")
+		h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s takes %s.\n", player.Name, item.Name))
+	}
+}
+
+func (h *TakeCommandHandler) takeFromContainer(db *sqlx.DB, player *players.Player, currentRoom *areas.Room, itemTarget Target, containerTarget Target) {
+	var containers []*items.Item
+	for _, item := range currentRoom.GetItems() {
+		if item.Container {
+			containers = append(containers, item)
 		}
-	} else {
+	}
+	found := Resolve(containerTarget, containers)
+	if len(found) == 0 || containerTarget.All {
 		display.PrintWithColor(player, "You don't see that here.\n", "reset")
+		return
+	}
+	container := found[0]
+
+	contents := Resolve(itemTarget, container.Contents)
+	if len(contents) == 0 {
+		display.PrintWithColor(player, fmt.Sprintf("You don't see that in %s.\n", container.Name), "reset")
+		return
+	}
+
+	for _, item := range contents {
+		if err := container.RemoveContent(item); err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("error removing item from %s: %v", container.Name, err), "reset")
+			continue
+		}
+		player.AddItem(db, item)
+
+		display.PrintWithColor(player, fmt.Sprintf("You take the %s from %s.\n", item.GetName(), container.Name), "reset")
+		h.Notifier.NotifyRoom(player.RoomUUID, player.UUID, fmt.Sprintf("\n%s takes %s from %s.\n", player.Name, item.Name, container.Name))
 	}
 }
 
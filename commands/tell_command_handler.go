@@ -6,7 +6,6 @@ import (
 	"mud/display"
 	"mud/notifications"
 	"mud/players"
-	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -16,24 +15,32 @@ type TellHandler struct {
 }
 
 func (h *TellHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
-	msg := strings.Join(arguments[1:], " ")
-	retrievedPlayer, err := players.GetPlayerByName(db, arguments[0])
-	if err != nil {
-		display.PrintWithColor(player, fmt.Sprintf("Error retrieving player UUID: %v\n", err), "danger")
+	// the message is as it was typed, quotes and capitals and all
+	msg := restOfLine(command, 2)
+	if len(arguments) == 0 || msg == "" {
+		display.PrintWithColor(player, "Tell whom what?\n", "reset")
 		return
 	}
 
-	if player.UUID == retrievedPlayer.UUID {
+	// a whole name beats the start of someone else's
+	recipient, ok := h.Notifier.Registry.GetByName(arguments[0])
+	if !ok {
+		target := ParseTarget(arguments[0])
+		found := Resolve(target, h.Notifier.Registry.All())
+		if len(found) == 0 || target.All {
+			display.PrintWithColor(player, fmt.Sprintf("%s isn't here\n", arguments[0]), "reset")
+			return
+		}
+		recipient = found[0]
+	}
+
+	if player.UUID == recipient.UUID {
 		display.PrintWithColor(player, "Talking to yourself again?\n", "reset")
 		return
 	}
 
-	if retrievedPlayer.LoggedIn {
-		display.PrintWithColor(player, fmt.Sprintf("You tell %s \"%s\"\n", arguments[0], msg), "reset")
-		h.Notifier.NotifyPlayer(retrievedPlayer.UUID, fmt.Sprintf("\n%s tells you \"%s\"\n", player.Name, msg))
-	} else {
-		display.PrintWithColor(player, fmt.Sprintf("%s isn't here\n", arguments[0]), "reset")
-	}
+	display.PrintWithColor(player, fmt.Sprintf("You tell %s \"%s\"\n", recipient.Name, msg), "reset")
+	h.Notifier.NotifyPlayer(recipient.UUID, fmt.Sprintf("\n%s tells you \"%s\"\n", player.Name, msg))
 }
 
 func (h *TellHandler) SetNotifier(notifier *notifications.Notifier) {
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"fmt"
+	"mud/areas"
+	"mud/display"
+	"mud/players"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// UnaliasCommandHandler removes one of the player's aliases.
+type UnaliasCommandHandler struct{}
+
+func (h *UnaliasCommandHandler) Execute(db *sqlx.DB, player *players.Player, command string, arguments []string, currentChannel chan areas.Action, updateChannel func(string)) {
+	if len(arguments) != 1 {
+		display.PrintWithColor(player, "Usage: unalias <name>\n", "reset")
+		return
+	}
+
+	name := strings.ToLower(arguments[0])
+	if _, ok := player.Aliases[name]; !ok {
+		display.PrintWithColor(player, fmt.Sprintf("You don't have an alias called %s.\n", name), "reset")
+		return
+	}
+
+	err := player.RemoveAlias(db, name)
+	if err != nil {
+		display.PrintWithColor(player, fmt.Sprintf("Error removing your alias: %v\n", err), "danger")
+		return
+	}
+	display.PrintWithColor(player, fmt.Sprintf("Removed the alias %s.\n", name), "reset")
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultPath is where the server and the seed tools look for their config
+// when they aren't told otherwise.  It doesn't have to exist.
+const DefaultPath = "mud.yaml"
+
+// What happens when somebody logs in as a character which is already playing.
+const (
+	TakeOverDuplicateLogin = "takeover"
+	RefuseDuplicateLogin   = "refuse"
+)
+
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	World    WorldConfig    `yaml:"world"`
+	Players  PlayersConfig  `yaml:"players"`
+}
+
+type ServerConfig struct {
+	SSHAddress  string `yaml:"ssh_address"`
+	HostKeyPath string `yaml:"host_key_path"`
+	// telnet is off when empty
+	TelnetAddress string `yaml:"telnet_address"`
+	// 0 never disconnects idle players
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`
+	DuplicateLogin string        `yaml:"duplicate_login"`
+}
+
+type DatabaseConfig struct {
+	Path string `yaml:"path"`
+	// used by the seed tools' -test flag
+	TestPath           string `yaml:"test_path"`
+	MonsterImportsPath string `yaml:"monster_imports_path"`
+	ClassImportsPath   string `yaml:"class_imports_path"`
+	RaceImportsPath    string `yaml:"race_imports_path"`
+}
+
+type WorldConfig struct {
+	StartAreaUUID string `yaml:"start_area"`
+	StartRoomUUID string `yaml:"start_room"`
+	// the start room when empty
+	RespawnRoomUUID string `yaml:"respawn_room"`
+	// how often players regenerate, in whole seconds
+	RegenInterval time.Duration `yaml:"regen_interval"`
+	// the directory of markdown help topics
+	HelpPath string `yaml:"help_path"`
+}
+
+type PlayersConfig struct {
+	DefaultColorProfileUUID string `yaml:"default_color_profile"`
+	StartingMovement        int32  `yaml:"starting_movement"`
+	MaxCharacters           int    `yaml:"max_characters"`
+}
+
+// Default is what the server runs with when nothing has been configured.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			SSHAddress:     ":2222",
+			HostKeyPath:    ".ssh/term_info_ed25519",
+			IdleTimeout:    30 * time.Minute,
+			DuplicateLogin: TakeOverDuplicateLogin,
+		},
+		Database: DatabaseConfig{
+			Path:               "./sql_database/mud.db",
+			TestPath:           "./sql_database/test_mud.db",
+			MonsterImportsPath: "./sql_database/monster_imports.db",
+			ClassImportsPath:   "./sql_database/class_imports.db",
+			RaceImportsPath:    "./sql_database/race_imports.db",
+		},
+		World: WorldConfig{
+			StartAreaUUID: "d71e8cf1-d5ba-426c-8915-4c7f5b22e3a9",
+			StartRoomUUID: "189a729d-4e40-4184-a732-e2c45c66ff46",
+			RegenInterval: 15 * time.Second,
+			HelpPath:      "./help",
+		},
+		Players: PlayersConfig{
+			DefaultColorProfileUUID: "2c7dfd5b-d160-42e0-accb-b77d9686dbea",
+			StartingMovement:        100,
+			MaxCharacters:           5,
+		},
+	}
+}
+
+// Load reads the config file over the defaults, then the environment over
+// that, and checks the result.  A missing file is fine as long as it is the
+// default one, anything else that was asked for has to be there.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("MUD_CONFIG")
+	}
+	required := path != ""
+	if path == "" {
+		path = DefaultPath
+	}
+
+	cfg := Default()
+	file, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		err = yaml.UnmarshalStrict(file, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config %s: %v", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !required:
+	default:
+		return nil, fmt.Errorf("error opening config %s: %v", path, err)
+	}
+
+	err = cfg.applyEnv(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envOverrides are the settings which can be changed without touching the
+// config file, ie in a container.
+func (c *Config) envOverrides() map[string]func(string) error {
+	return map[string]func(string) error{
+		"MUD_SSH_ADDRESS":           setString(&c.Server.SSHAddress),
+		"MUD_HOST_KEY_PATH":         setString(&c.Server.HostKeyPath),
+		"MUD_TELNET_ADDRESS":        setString(&c.Server.TelnetAddress),
+		"MUD_IDLE_TIMEOUT":          setDuration(&c.Server.IdleTimeout),
+		"MUD_DUPLICATE_LOGIN":       setString(&c.Server.DuplicateLogin),
+		"MUD_DATABASE_PATH":         setString(&c.Database.Path),
+		"MUD_TEST_DATABASE_PATH":    setString(&c.Database.TestPath),
+		"MUD_START_AREA":            setString(&c.World.StartAreaUUID),
+		"MUD_START_ROOM":            setString(&c.World.StartRoomUUID),
+		"MUD_RESPAWN_ROOM":          setString(&c.World.RespawnRoomUUID),
+		"MUD_REGEN_INTERVAL":        setDuration(&c.World.RegenInterval),
+		"MUD_HELP_PATH":             setString(&c.World.HelpPath),
+		"MUD_DEFAULT_COLOR_PROFILE": setString(&c.Players.DefaultColorProfileUUID),
+		"MUD_STARTING_MOVEMENT": func(value string) error {
+			movement, err := strconv.ParseInt(value, 10, 32)
+			c.Players.StartingMovement = int32(movement)
+			return err
+		},
+		"MUD_MAX_CHARACTERS": func(value string) error {
+			var err error
+			c.Players.MaxCharacters, err = strconv.Atoi(value)
+			return err
+		},
+	}
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	for name, set := range c.envOverrides() {
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		err := set(value)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func setString(field *string) func(string) error {
+	return func(value string) error {
+		*field = value
+		return nil
+	}
+}
+
+func setDuration(field *time.Duration) func(string) error {
+	return func(value string) error {
+		var err error
+		*field, err = time.ParseDuration(value)
+		return err
+	}
+}
+
+// RespawnRoom is the room dead players wake up in.
+func (c *Config) RespawnRoom() string {
+	if c.World.RespawnRoomUUID == "" {
+		return c.World.StartRoomUUID
+	}
+	return c.World.RespawnRoomUUID
+}
+
+// Validate reports everything wrong with the config at once, so it doesn't
+// take several restarts to fix.
+func (c *Config) Validate() error {
+	var problems []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Errorf(format, args...))
+		}
+	}
+	isUUID := func(value string) bool {
+		_, err := uuid.Parse(value)
+		return err == nil
+	}
+
+	check(c.Server.SSHAddress != "", "server.ssh_address is required")
+	check(c.Server.HostKeyPath != "", "server.host_key_path is required")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout can't be negative")
+	check(c.Server.DuplicateLogin == TakeOverDuplicateLogin || c.Server.DuplicateLogin == RefuseDuplicateLogin,
+		"server.duplicate_login must be %s or %s, not %q", TakeOverDuplicateLogin, RefuseDuplicateLogin, c.Server.DuplicateLogin)
+	check(c.Database.Path != "", "database.path is required")
+	check(isUUID(c.World.StartAreaUUID), "world.start_area %q isn't a uuid", c.World.StartAreaUUID)
+	check(isUUID(c.World.StartRoomUUID), "world.start_room %q isn't a uuid", c.World.StartRoomUUID)
+	check(c.World.RespawnRoomUUID == "" || isUUID(c.World.RespawnRoomUUID), "world.respawn_room %q isn't a uuid", c.World.RespawnRoomUUID)
+	check(c.World.RegenInterval >= time.Second, "world.regen_interval must be at least 1s")
+	check(isUUID(c.Players.DefaultColorProfileUUID), "players.default_color_profile %q isn't a uuid", c.Players.DefaultColorProfileUUID)
+	check(c.Players.StartingMovement > 0, "players.starting_movement must be more than 0")
+	check(c.Players.MaxCharacters > 0, "players.max_characters must be more than 0")
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(problems...))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mud.yaml")
+	err := os.WriteFile(path, []byte(`
+server:
+  ssh_address: ":2300"
+world:
+  regen_interval: 5s
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MUD_SSH_ADDRESS", ":2400")
+	t.Setenv("MUD_STARTING_MOVEMENT", "80")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	// the environment wins over the file
+	if cfg.Server.SSHAddress != ":2400" {
+		t.Errorf("expected the ssh address from the environment, got %q", cfg.Server.SSHAddress)
+	}
+	if cfg.World.RegenInterval != 5*time.Second {
+		t.Errorf("expected the regen interval from the file, got %s", cfg.World.RegenInterval)
+	}
+	if cfg.Players.StartingMovement != 80 {
+		t.Errorf("expected starting movement 80, got %d", cfg.Players.StartingMovement)
+	}
+	// and anything not mentioned keeps its default
+	if cfg.Database.Path != Default().Database.Path {
+		t.Errorf("expected the default database path, got %q", cfg.Database.Path)
+	}
+	if cfg.RespawnRoom() != cfg.World.StartRoomUUID {
+		t.Errorf("expected players to respawn in the start room, got %q", cfg.RespawnRoom())
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if _, err := Load(""); err != nil {
+		t.Errorf("expected the defaults without a config file, got %v", err)
+	}
+	if _, err := Load("missing.yaml"); err == nil {
+		t.Error("expected an error for a config file which was asked for but isn't there")
+	}
+}
+
+func TestLoadRejectsUnknownSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mud.yaml")
+	err := os.WriteFile(path, []byte("server:\n  ssh_adress: \":2300\"\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for a misspelt setting")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.Server.DuplicateLogin = "ignore"
+	cfg.World.StartRoomUUID = "start"
+	cfg.World.RegenInterval = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected an invalid config")
+	}
+	for _, setting := range []string{"server.duplicate_login", "world.start_room", "world.regen_interval"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("expected %s to be reported, got %v", setting, err)
+		}
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Errorf("expected the defaults to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+//go:build unix
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"mud/areas"
+	"mud/display"
+	"mud/players"
+	"mud/transport"
+	"mud/world_state"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// the new server finds the sessions kept open for it in the file named by this
+const copyoverEnv = "MUD_COPYOVER"
+
+// copyoverSession is a connection which stays open while the server restarts,
+// and the character playing on it.
+type copyoverSession struct {
+	FD          int    `json:"fd"`
+	PlayerName  string `json:"player_name"`
+	GMCPEnabled bool   `json:"gmcp_enabled"`
+
+	player *players.Player
+	// held on to until the restart, the socket closes if it is garbage collected
+	file *os.File
+}
+
+// prepareCopyover gets the player's connection ready to be handed on to the
+// new server.  Only telnet connections can be, ssh sessions are encrypted with
+// keys which only this process has.
+func prepareCopyover(player *players.Player) (copyoverSession, error) {
+	conn, ok := player.GetSession().(*transport.TelnetConn)
+	if !ok {
+		return copyoverSession{}, fmt.Errorf("%s isn't on telnet", player.Name)
+	}
+
+	file, err := conn.File()
+	if err != nil {
+		return copyoverSession{}, err
+	}
+	// let the socket survive the exec
+	_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, file.Fd(), syscall.F_SETFD, 0)
+	if errno != 0 {
+		file.Close()
+		return copyoverSession{}, errno
+	}
+
+	return copyoverSession{
+		FD:          int(file.Fd()),
+		PlayerName:  player.Name,
+		GMCPEnabled: conn.GMCPEnabled(),
+		player:      player,
+		file:        file,
+	}, nil
+}
+
+func writeCopyoverState(sessions []copyoverSession) error {
+	state, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("mud-copyover-%d.json", os.Getpid()))
+	err = os.WriteFile(path, state, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Setenv(copyoverEnv, path)
+}
+
+// resumeCopyover logs the players who were kept connected through a reboot
+// straight back in.
+func (s *Server) resumeCopyover(db *sqlx.DB, router CommandRouterInterface, areaChannels map[string]chan areas.Action, worldState *world_state.WorldState) {
+	path := os.Getenv(copyoverEnv)
+	if path == "" {
+		return
+	}
+	os.Unsetenv(copyoverEnv)
+	defer os.Remove(path)
+
+	state, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("error reading the copyover: %v", err)
+		return
+	}
+	var sessions []copyoverSession
+	err = json.Unmarshal(state, &sessions)
+	if err != nil {
+		log.Printf("error reading the copyover: %v", err)
+		return
+	}
+
+	for _, session := range sessions {
+		file := os.NewFile(uintptr(session.FD), session.PlayerName)
+		conn, err := transport.ResumeTelnetConn(file, session.GMCPEnabled)
+		file.Close()
+		if err != nil {
+			log.Printf("error resuming %s's connection: %v", session.PlayerName, err)
+			continue
+		}
+
+		player, err := players.Reconnect(conn, db, session.PlayerName)
+		if err != nil {
+			fmt.Fprintf(conn, "\nSorry, you couldn't be logged back in: %v\n", err)
+			conn.Close()
+			continue
+		}
+
+		go func() {
+			defer conn.Close()
+			if !s.admit(player, conn) {
+				return
+			}
+			defer s.release(db, player)
+
+			display.PrintWithColor(player, "\nThe server is back!\n", "primary")
+			s.play(conn, player, router, db, areaChannels, worldState)
+		}()
+	}
+	log.Printf("Resumed %d connections after the copyover", len(sessions))
+}
+
+// restart replaces the running server with a fresh copy of itself, started
+// with the same arguments.  It only returns when that couldn't be done.
+func restart() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error finding the server to restart: %v", err)
+	}
+	err = syscall.Exec(executable, os.Args, os.Environ())
+	return fmt.Errorf("error restarting the server: %v", err)
+}
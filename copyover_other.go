@@ -0,0 +1,36 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors"
+	"log"
+	"mud/areas"
+	"mud/players"
+	"mud/world_state"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// copyovers need exec, so everyone has to reconnect after a reboot here.
+type copyoverSession struct {
+	player *players.Player
+	file   *os.File
+}
+
+func prepareCopyover(player *players.Player) (copyoverSession, error) {
+	return copyoverSession{}, errors.New("copyover isn't supported on this platform")
+}
+
+func writeCopyoverState(sessions []copyoverSession) error {
+	return nil
+}
+
+func (s *Server) resumeCopyover(db *sqlx.DB, router CommandRouterInterface, areaChannels map[string]chan areas.Action, worldState *world_state.WorldState) {
+}
+
+func restart() error {
+	log.Println("Rebooting isn't supported on this platform, start the server again by hand")
+	return nil
+}
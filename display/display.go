@@ -2,8 +2,7 @@ package display
 
 import (
 	"fmt"
-
-	"github.com/charmbracelet/ssh"
+	"mud/transport"
 )
 
 const (
@@ -32,12 +31,12 @@ func Colorize(text string, color string) string {
 
 type ProfilePlayer interface {
 	GetColorProfileColor(string) string
-	GetSession() ssh.Session
+	GetSession() transport.Conn
 }
 
 type colorProfileAndConnectionGetter interface {
 	GetColorProfileColor(string) string
-	GetSession() ssh.Session
+	GetSession() transport.Conn
 }
 
 func PrintWithColor(player colorProfileAndConnectionGetter, text string, colorUse string) {
@@ -3,6 +3,7 @@ package display
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 type MenuContents struct {
@@ -66,7 +67,7 @@ func splitString(inputString string, maxWidth int, left string, right string, de
 				}
 			}
 		} else if len(line)+len(word) > maxWidth {
-			repeatCount := maxWidth - len(line) + 3
+			repeatCount := maxWidth - utf8.RuneCountInString(line) + 2
 			if repeatCount < 0 {
 				repeatCount = 0
 			}
@@ -77,7 +78,7 @@ func splitString(inputString string, maxWidth int, left string, right string, de
 
 			line = fmt.Sprintf("%s%s%s", left, frontPadding, word)
 			if idx == len(words)-1 {
-				repeatCount := maxWidth - len(line) + 3
+				repeatCount := maxWidth - utf8.RuneCountInString(line) + 2
 				if repeatCount < 0 {
 					repeatCount = 0
 				}
@@ -92,7 +93,7 @@ func splitString(inputString string, maxWidth int, left string, right string, de
 				line += fmt.Sprintf(" %s", word)
 			}
 			if idx == len(words)-1 {
-				repeatCount := maxWidth - len(line) + 3
+				repeatCount := maxWidth - utf8.RuneCountInString(line) + 2
 				if repeatCount < 0 {
 					repeatCount = 0
 				}
@@ -109,6 +110,23 @@ func splitString(inputString string, maxWidth int, left string, right string, de
 	return lines
 }
 
+// delimiterFor is what goes in front of the element, ie its number.
+func delimiterFor(contents MenuContents, idx int) string {
+	if contents.Delimiter == nil || *contents.Delimiter == "none" {
+		return ""
+	} else if *contents.Delimiter == "number" {
+		spacer := ""
+		if len(contents.Elements) > 9 && idx < 9 {
+			spacer = " "
+		}
+		return fmt.Sprintf("%d.%s", idx+1, spacer)
+	} else if *contents.Delimiter == "letter" {
+		letter := rune('a' + idx)
+		return fmt.Sprintf("%c.", letter)
+	}
+	return *contents.Delimiter
+}
+
 func PrintMenu(player ProfilePlayer, contents MenuContents) {
 	Newline(player)
 	topLeftDbl := string('╔')
@@ -150,8 +168,9 @@ func PrintMenu(player ProfilePlayer, contents MenuContents) {
 		width = len(*contents.Title) + 2
 	}
 
-	for _, element := range contents.Elements {
-		lineLength := len(element) + 2
+	for idx, element := range contents.Elements {
+		// room for the borders and the delimiter either side of the element
+		lineLength := len(element) + len(delimiterFor(contents, idx)) + 3
 		if lineLength > width {
 			width = lineLength
 		}
@@ -180,21 +199,7 @@ func PrintMenu(player ProfilePlayer, contents MenuContents) {
 
 	var elementsToPrint []string
 	for idx, element := range contents.Elements {
-		var elementsToAdd []string
-		if contents.Delimiter == nil || *contents.Delimiter == "none" {
-			elementsToAdd = splitString(element, width, vertical, vertical, "")
-		} else if *contents.Delimiter == "number" {
-			spacer := ""
-			if len(contents.Elements) > 9 && idx < 9 {
-				spacer = " "
-			}
-			elementsToAdd = splitString(element, width, vertical, vertical, fmt.Sprintf("%d.%s", idx+1, spacer))
-		} else if *contents.Delimiter == "letter" {
-			letter := rune('a' + idx)
-			elementsToAdd = splitString(element, width, vertical, vertical, fmt.Sprintf("%c.", letter))
-		} else {
-			elementsToAdd = splitString(element, width, vertical, vertical, *contents.Delimiter)
-		}
+		elementsToAdd := splitString(element, width, vertical, vertical, delimiterFor(contents, idx))
 		elementsToPrint = append(elementsToPrint, elementsToAdd...)
 	}
 
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"mud/seed"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var notFileNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// areaFileName turns the area's name into a file name, ie "The Arena" into
+// the_arena.yml.
+func areaFileName(area *seed.AreaImport) string {
+	name := strings.Trim(notFileNameChars.ReplaceAllString(strings.ToLower(area.Name), "_"), "_")
+	if name == "" {
+		name = area.UUID
+	}
+	return name + ".yml"
+}
+
+// runExport writes every area in the database out as an area file, so that
+// the world can be edited and imported again.
+func runExport(args []string) error {
+	flags, configPath := newFlagSet("export")
+	dir := flags.String("dir", "areas/export", "directory to write the area files to")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usage("export")
+	}
+
+	_, db, err := openConfigured(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	areas, err := seed.ExportAreas(db)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, area := range areas {
+		data, err := yaml.Marshal(area)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %v", area.Name, err)
+		}
+		path := filepath.Join(*dir, areaFileName(area))
+		err = os.WriteFile(path, append([]byte("---\n"), data...), 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Exported %s to %s\n", area.Name, path)
+	}
+	return nil
+}
+
+// runImport reads area files into the database, refusing to overwrite an area
+// which is already there unless told to.
+func runImport(args []string) error {
+	flags, configPath := newFlagSet("import")
+	replace := flags.Bool("replace", false, "replace areas which are already in the database")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usage("import")
+	}
+
+	cfg, db, err := openConfigured(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = migrateUp(db)
+	if err != nil {
+		return err
+	}
+	err = seed.ImportAreas(db, cfg, flags.Args(), *replace)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d area files\n", flags.NArg())
+	return nil
+}
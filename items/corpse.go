@@ -0,0 +1,60 @@
+package items
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// CorpseDecay is how long a corpse lies around before it crumbles away.
+var CorpseDecay = 10 * time.Minute
+
+// NewCorpse leaves a corpse in the room, holding whatever the deceased was
+// carrying.
+func NewCorpse(db *sqlx.DB, name string, roomUUID string, contents []*Item) (*Item, error) {
+	corpseName := fmt.Sprintf("the corpse of %s", name)
+	corpseDescription := fmt.Sprintf("The lifeless body of %s lies here.", name)
+	corpse := NewItem(uuid.NewString(), corpseName, corpseDescription, []string{})
+	corpse.Container = true
+	corpse.DecaysAt = time.Now().Add(CorpseDecay)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("INSERT INTO items (uuid, name, description, equipment_slots, container, decays_at) VALUES (?, ?, ?, ?, ?, ?)",
+		corpse.UUID, corpse.Name, corpse.Description, "[]", true, corpse.DecaysAt.Unix())
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to insert corpse: %v", err)
+	}
+
+	_, err = tx.Exec("INSERT INTO item_locations (item_uuid, room_uuid, player_uuid, container_uuid) VALUES (?, ?, '', '')", corpse.UUID, roomUUID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to insert corpse location: %v", err)
+	}
+
+	for _, item := range contents {
+		_, err = tx.Exec("UPDATE item_locations SET room_uuid = '', player_uuid = '', container_uuid = ? WHERE item_uuid = ?", corpse.UUID, item.UUID)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to move %s into corpse: %v", item.Name, err)
+		}
+		corpse.Contents = append(corpse.Contents, item)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return corpse, nil
+}
+
+// HasDecayed is whether the item should have crumbled away by now.
+func (item *Item) HasDecayed(now time.Time) bool {
+	return !item.DecaysAt.IsZero() && !now.Before(item.DecaysAt)
+}
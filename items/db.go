@@ -4,18 +4,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 func GetItemsInRoom(db *sqlx.DB, roomUUID string) ([]*Item, error) {
 	query := `
-		SELECT i.uuid, i.name, i.description, i.equipment_slots
+		SELECT i.uuid, i.name, i.description, i.equipment_slots, COALESCE(i.container, FALSE), COALESCE(i.decays_at, 0)
 		FROM item_locations il
 		JOIN items i ON il.item_uuid = i.uuid
 		WHERE il.room_uuid = ?
 	`
-	rows, err := db.Query(query, roomUUID)
+	return getItemsAtLocation(db, query, roomUUID)
+}
+
+func GetItemsInContainer(db *sqlx.DB, containerUUID string) ([]*Item, error) {
+	query := `
+		SELECT i.uuid, i.name, i.description, i.equipment_slots, COALESCE(i.container, FALSE), COALESCE(i.decays_at, 0)
+		FROM item_locations il
+		JOIN items i ON il.item_uuid = i.uuid
+		WHERE il.container_uuid = ?
+	`
+	return getItemsAtLocation(db, query, containerUUID)
+}
+
+func getItemsAtLocation(db *sqlx.DB, query string, locationUUID string) ([]*Item, error) {
+	rows, err := db.Query(query, locationUUID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %v", err)
 	}
@@ -26,10 +41,14 @@ func GetItemsInRoom(db *sqlx.DB, roomUUID string) ([]*Item, error) {
 		var item Item
 		var equipmentSlotsJSON string
 		var equipmentSlots []string
-		err := rows.Scan(&item.UUID, &item.Name, &item.Description, &equipmentSlotsJSON)
+		var decaysAt int64
+		err := rows.Scan(&item.UUID, &item.Name, &item.Description, &equipmentSlotsJSON, &item.Container, &decaysAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %v", err)
 		}
+		if decaysAt > 0 {
+			item.DecaysAt = time.Unix(decaysAt, 0)
+		}
 
 		err = json.Unmarshal([]byte(equipmentSlotsJSON), &equipmentSlots)
 		if err != nil {
@@ -65,15 +84,25 @@ func GetItemsInRoom(db *sqlx.DB, roomUUID string) ([]*Item, error) {
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating over rows: %v", err)
 	}
+	rows.Close()
+
+	for _, item := range items {
+		if item.Container {
+			item.Contents, err = GetItemsInContainer(db, item.UUID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	return items, nil
 }
 
 func (item *Item) SetLocation(db *sqlx.DB, playerUUID string, roomUUID string) error {
 	var query string
 	if playerUUID != "" {
-		query = fmt.Sprintf("UPDATE item_locations SET room_uuid = '', player_uuid = '%s' WHERE item_uuid = '%s'", playerUUID, item.UUID)
+		query = fmt.Sprintf("UPDATE item_locations SET room_uuid = '', player_uuid = '%s', container_uuid = '' WHERE item_uuid = '%s'", playerUUID, item.UUID)
 	} else {
-		query = fmt.Sprintf("UPDATE item_locations SET room_uuid = '%s', player_uuid = '' WHERE item_uuid = '%s'", roomUUID, item.UUID)
+		query = fmt.Sprintf("UPDATE item_locations SET room_uuid = '%s', player_uuid = '', container_uuid = '' WHERE item_uuid = '%s'", roomUUID, item.UUID)
 	}
 	_, err := db.Exec(query)
 	if err != nil {
@@ -82,6 +111,44 @@ func (item *Item) SetLocation(db *sqlx.DB, playerUUID string, roomUUID string) e
 	return nil
 }
 
+// SetContainer puts the item inside of another item, ie a corpse or a bag.
+func (item *Item) SetContainer(db *sqlx.DB, containerUUID string) error {
+	_, err := db.Exec("UPDATE item_locations SET room_uuid = '', player_uuid = '', container_uuid = ? WHERE item_uuid = ?", containerUUID, item.UUID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SpawnItemInRoom makes a new item from the template and drops it in the room.
+func SpawnItemInRoom(db *sqlx.DB, templateUUID string, roomUUID string) (*Item, error) {
+	item, err := NewItemFromTemplate(db, templateUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("INSERT INTO item_locations (item_uuid, room_uuid, player_uuid, container_uuid) VALUES (?, ?, '', '')", item.UUID, roomUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert item location: %v", err)
+	}
+	return item, nil
+}
+
+func CountItemsFromTemplateInRoom(db *sqlx.DB, templateUUID string, roomUUID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM item_locations il
+		JOIN items i ON il.item_uuid = i.uuid
+		WHERE i.template_uuid = ? AND il.room_uuid = ?
+	`
+	var count int
+	err := db.QueryRow(query, templateUUID, roomUUID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count items: %v", err)
+	}
+	return count, nil
+}
+
 func GetItemsForPlayer(db *sqlx.DB, playerUUID string) ([]*Item, error) {
 	query := `
 		SELECT i.uuid, i.name, i.description, i.equipment_slots
@@ -157,3 +224,33 @@ func GetItemByNameForPlayer(db *sqlx.DB, itemName string, playerUUID string) (*I
 
 	return nil, fmt.Errorf("item not found")
 }
+
+// DeleteItem destroys the item, along with anything inside of it.
+func DeleteItem(db *sqlx.DB, item *Item) error {
+	for _, content := range item.Contents {
+		err := DeleteItem(db, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := db.Exec("DELETE FROM item_locations WHERE item_uuid = ?", item.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to delete location of item %s: %v", item.UUID, err)
+	}
+	_, err = db.Exec("DELETE FROM items WHERE uuid = ?", item.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to delete item %s: %v", item.UUID, err)
+	}
+	return nil
+}
+
+// FindTemplateUUID looks up an item template by its uuid or its name.
+func FindTemplateUUID(db *sqlx.DB, template string) (string, error) {
+	var templateUUID string
+	err := db.Get(&templateUUID, "SELECT uuid FROM item_templates WHERE uuid = ? OR LOWER(name) = LOWER(?) LIMIT 1", template, template)
+	if err != nil {
+		return "", fmt.Errorf("no item template %s", template)
+	}
+	return templateUUID, nil
+}
@@ -3,6 +3,8 @@ package items
 import (
 	"encoding/json"
 	"fmt"
+	"mud/utilities"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -32,9 +34,9 @@ func NewItemFromTemplate(db *sqlx.DB, templateUUID string) (*Item, error) {
 	}
 
 	itemUUID := uuid.NewString()
-	query = `INSERT INTO items (uuid, name, description, equipment_slots)
-				VALUES (?, ?, ?, ?)`
-	_, err = db.Exec(query, itemUUID, name, description, equipmentSlotsJSON)
+	query = `INSERT INTO items (uuid, name, description, equipment_slots, template_uuid)
+				VALUES (?, ?, ?, ?, ?)`
+	_, err = db.Exec(query, itemUUID, name, description, equipmentSlotsJSON, templateUUID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert item: %v", err)
 	}
@@ -63,6 +65,10 @@ type Item struct {
 	Name           string
 	Description    string
 	EquipmentSlots []string
+	Container      bool
+	Contents       []*Item
+	// when the item crumbles away, zero for items which last
+	DecaysAt time.Time
 }
 
 func (item *Item) GetUUID() string {
@@ -81,6 +87,22 @@ func (item Item) GetEquipmentSlots() []string {
 	return item.EquipmentSlots
 }
 
+// Matches checks whether the words in the item's name start with the words
+// of the keyword, so "corpse" matches "the corpse of Reg".
+func (item *Item) Matches(keyword string) bool {
+	return utilities.MatchesName(item.Name, keyword)
+}
+
+func (item *Item) RemoveContent(content *Item) error {
+	for idx := range item.Contents {
+		if item.Contents[idx].UUID == content.UUID {
+			item.Contents = append(item.Contents[:idx], item.Contents[idx+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("item %s is not in %s", content.UUID, item.UUID)
+}
+
 // TODO: Don't need this?
 // type EquippedItem struct {
 // 	*Item
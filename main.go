@@ -2,18 +2,22 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"mud/areas"
 	"mud/commands"
+	"mud/config"
 	"mud/display"
 	"mud/notifications"
 	"mud/players"
+	"mud/sessions"
+	"mud/transport"
 	"mud/world_state"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/ssh"
-	"github.com/charmbracelet/wish"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -23,16 +27,59 @@ type CommandRouterInterface interface {
 }
 
 type Server struct {
-	connections map[string]*players.Player
+	connections *sessions.Registry
+	config      *config.Config
+	// every Area.Run, so that shutting down can wait for them to stop
+	areaLoops sync.WaitGroup
+	// closed once the area loops have been told to stop
+	areasDone <-chan struct{}
+	// each area's action queue, by area uuid
+	areaChannels map[string]chan areas.Action
 }
 
-func NewServer() *Server {
+func NewServer(cfg *config.Config) *Server {
+	connections := sessions.NewRegistry()
+	players.OnMove(connections.Move)
 	return &Server{
-		connections: make(map[string]*players.Player),
+		connections: connections,
+		config:      cfg,
 	}
 }
 
-func (s *Server) handleConnection(session ssh.Session, router CommandRouterInterface, db *sqlx.DB, areaChannels map[string]chan areas.Action, roomToAreaMap map[string]string, worldState *world_state.WorldState) {
+// admit adds the newly logged in player to the game.  If their character is
+// already being played, either the old connection is dropped or this one is
+// turned away, which they're told about on the connection.
+func (s *Server) admit(player *players.Player, conn transport.Conn) bool {
+	if s.config.Server.DuplicateLogin == config.RefuseDuplicateLogin {
+		if !s.connections.AddIfAbsent(player) {
+			fmt.Fprintf(conn, "%s is already logged in.\n", player.Name)
+			return false
+		}
+		return true
+	}
+
+	previous, ok := s.connections.Replace(player)
+	if ok {
+		fmt.Fprintf(previous.GetSession(), "\n%s has been logged in from somewhere else.\n", player.Name)
+		previous.GetSession().Close()
+	}
+	return true
+}
+
+// release takes the player back out of the game, and logs them out unless a
+// newer login has taken their character over.
+func (s *Server) release(db *sqlx.DB, player *players.Player) {
+	if !s.connections.RemoveIfCurrent(player) {
+		player.GetSession().Close()
+		return
+	}
+	err := player.Logout(db)
+	if err != nil {
+		fmt.Printf("error updating %s's logged_in status: %v\n", player.Name, err)
+	}
+}
+
+func (s *Server) handleConnection(session transport.Conn, router CommandRouterInterface, db *sqlx.DB, areaChannels map[string]chan areas.Action, roomToAreaMap map[string]string, worldState *world_state.WorldState) {
 	defer session.Close()
 
 	player, err := players.LoginPlayer(session, db)
@@ -44,73 +91,120 @@ func (s *Server) handleConnection(session ssh.Session, router CommandRouterInter
 		return
 	}
 
-	defer func() {
-		err := player.Logout(db)
-		if err != nil {
-			fmt.Fprintf(session, "Error updating player logged_in status: %v\n", err)
-		}
-	}()
+	if !s.admit(player, session) {
+		return
+	}
+	defer s.release(db, player)
 
-	s.connections[player.UUID] = player
-	defer delete(s.connections, player.UUID)
+	s.play(session, player, router, db, areaChannels, worldState)
+}
 
+// play puts the player in their room and runs what they type until they leave.
+func (s *Server) play(session transport.Conn, player *players.Player, router CommandRouterInterface, db *sqlx.DB, areaChannels map[string]chan areas.Action, worldState *world_state.WorldState) {
 	currentRoom := worldState.GetRoom(player.RoomUUID, false)
 	currentRoom.AddPlayer(player)
 
 	notifyPlayersInRoomThatNewPlayerHasJoined(player, s.connections)
+	sendLoginGMCP(player, worldState)
 
-	ch := areaChannels[player.AreaUUID]
+	// queued commands run on the area's goroutine, so rather than having them
+	// swap the channel out from under us, look it up from the player's area
+	// before every command.
+	updateChannel := func(newArea string) {}
 
-	updateChannel := func(newArea string) {
-		ch = areaChannels[newArea]
-	}
-
-	router.HandleCommand(db, player, bytes.NewBufferString("look").Bytes(), ch, updateChannel)
+	router.HandleCommand(db, player, bytes.NewBufferString("look").Bytes(), areaChannels[player.AreaUUID], updateChannel)
 
+	// clients keep their own history to scroll through, but !! is ours
+	var history transport.History
 	for {
-		display.PrintWithColor(player, fmt.Sprintf("\nHP: %d Mvt: %d> ", player.HP, player.Movement), "primary")
-		buf := make([]byte, 1024)
-		n, err := session.Read(buf)
+		player.ShowPrompt()
+		line, err := session.ReadLine()
 		if err != nil {
 			fmt.Println(err)
 			break
 		}
 
-		router.HandleCommand(db, player, buf[:n], ch, updateChannel)
+		command, err := history.Recall(line)
+		if err != nil {
+			display.PrintWithColor(player, fmt.Sprintf("Nothing was run, %v.\n", err), "danger")
+			continue
+		}
+		if command != strings.TrimSpace(line) {
+			display.PrintWithColor(player, fmt.Sprintf("%s\n", command), "reset")
+		}
+		history.Add(command)
+
+		router.HandleCommand(db, player, []byte(command), areaChannels[player.AreaUUID], updateChannel)
 	}
 }
 
-func notifyPlayersInRoomThatNewPlayerHasJoined(player *players.Player, connections map[string]*players.Player) {
+func notifyPlayersInRoomThatNewPlayerHasJoined(player *players.Player, connections *sessions.Registry) {
 	var playersInRoom []*players.Player
-	for _, p := range connections {
-		if p.RoomUUID == player.RoomUUID && p.UUID != player.UUID {
+	for _, p := range connections.InRoom(player.RoomUUID) {
+		if p.UUID != player.UUID {
 			playersInRoom = append(playersInRoom, p)
 		}
 	}
 
 	for _, p := range playersInRoom {
 		fmt.Fprintf(p.GetSession(), "\n%s has joined the game.\n", player.Name)
-		display.PrintWithColor(p, fmt.Sprintf("\nHP: %d Mvt: %d> ", player.HP, player.Movement), "primary")
+		p.ShowPrompt()
 	}
 }
 
-func openDatabase() (*sqlx.DB, error) {
-	db, err := sqlx.Connect("sqlite3", "./sql_database/mud.db")
-	if err != nil {
-		return nil, err
+// sendLoginGMCP gives clients which speak GMCP everything they need to draw
+// their gauges and maps straight away, after that it is sent as it changes.
+func sendLoginGMCP(player *players.Player, worldState *world_state.WorldState) {
+	player.SendVitals()
+	player.SendItems()
+	if room := worldState.GetRoom(player.RoomUUID, false); room != nil {
+		player.SendGMCP("Room.Info", room.Info())
 	}
+}
 
-	err = db.Ping()
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println("Database opened successfully")
+// sendRoomInfoOnMove keeps GMCP maps in step with players moving around.
+func sendRoomInfoOnMove(connections *sessions.Registry, worldState *world_state.WorldState) {
+	players.OnMove(func(playerUUID string, areaUUID string, roomUUID string) {
+		player, ok := connections.Get(playerUUID)
+		if !ok {
+			return
+		}
+		if room := worldState.GetRoom(roomUUID, false); room != nil {
+			player.SendGMCP("Room.Info", room.Info())
+		}
+	})
+}
+
+// setPromptContext lets prompts show what's going on where the player is.
+func setPromptContext(worldState *world_state.WorldState) {
+	players.SetPromptContext(func(player *players.Player) players.PromptContext {
+		room := worldState.GetRoom(player.RoomUUID, false)
+		if room == nil {
+			return players.PromptContext{}
+		}
 
-	return db, nil
+		info := room.Info()
+		context := players.PromptContext{RoomName: room.Name, AreaName: info.Area}
+		if context.AreaName == "" {
+			if area := worldState.GetArea(room.AreaUUID); area != nil {
+				context.AreaName = area.Name
+			}
+		}
+		for direction := range info.Exits {
+			context.Exits = append(context.Exits, direction)
+		}
+
+		// the fight belongs to the area's goroutine, so go by what it last
+		// published rather than looking at room.Combat from here
+		if target, ok := areas.TargetOf(player.UUID); ok {
+			context.Fighting, context.TargetHP, context.TargetMaxHP = true, target.HP, target.MaxHP
+		}
+		return context
+	})
 }
 
 // TODO should this function be moved into the world_state package?
-func loadAreas(db *sqlx.DB, server *Server) (map[string]*areas.Area, map[string]string, map[string]chan areas.Action, error) {
+func loadAreas(ctx context.Context, db *sqlx.DB, server *Server, notifier *notifications.Notifier) (map[string]*areas.Area, map[string]string, map[string]chan areas.Action, error) {
 	areaInstances := make(map[string]*areas.Area)
 	areaInstancesInterface := make(map[string]*areas.Area)
 	roomToAreaMap := make(map[string]string)
@@ -134,9 +228,14 @@ func loadAreas(db *sqlx.DB, server *Server) (map[string]*areas.Area, map[string]
 		_, ok := areaInstances[areaUUID]
 		if !ok {
 			areaInstances[areaUUID] = areas.NewArea(areaUUID, name, description)
+			areaInstances[areaUUID].RegenInterval = int(server.config.World.RegenInterval / time.Second)
 			areaInstancesInterface[areaUUID] = areaInstances[areaUUID]
 			areaChannels[areaUUID] = make(chan areas.Action)
-			go areaInstances[areaUUID].Run(db, areaChannels[areaUUID], server.connections)
+			server.areaLoops.Add(1)
+			go func(area *areas.Area, ch chan areas.Action) {
+				defer server.areaLoops.Done()
+				area.Run(ctx, db, ch, server.connections, notifier)
+			}(areaInstances[areaUUID], areaChannels[areaUUID])
 		}
 		roomToAreaMap[roomUUID] = areaUUID
 	}
@@ -155,108 +254,13 @@ func logoutAllPlayers(db *sqlx.DB) {
 }
 
 func main() {
-	db, err := openDatabase()
-	if err != nil {
-		log.Fatalln(err)
-	}
-	defer db.Close()
-
-	server := NewServer()
-	notifier := notifications.NewNotifier(server.connections)
-
-	logoutAllPlayers(db)
-	areaInstances, roomToAreaMap, areaChannels, err := loadAreas(db, server)
-	if err != nil {
-		log.Fatalf("error loading areas: %v", err)
-	}
-
-	worldState := world_state.NewWorldState(areaInstances, roomToAreaMap, db)
-
-	s, err := wish.NewServer(
-		wish.WithAddress(":2222"),
-		wish.WithHostKeyPath(".ssh/term_info_ed25519"),
-		wish.WithMiddleware(
-			BubbleteaMUD(db, server, notifier, areaChannels, roomToAreaMap, worldState),
-		),
-	)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	log.Println("Starting SSH server on :2222")
-	log.Fatalln(s.ListenAndServe())
+	os.Exit(run(os.Args[1:]))
 }
 
-type mudModel struct {
-	db            *sqlx.DB
-	server        *Server
-	notifier      *notifications.Notifier
-	areaChannels  map[string]chan areas.Action
-	roomToAreaMap map[string]string
-	worldState    *world_state.WorldState
-	router        CommandRouterInterface
-	player        *players.Player
-	session       ssh.Session
+func (s *Server) newRouter(notifier *notifications.Notifier, worldState *world_state.WorldState) *commands.CommandRouter {
+	router := commands.NewCommandRouter()
+	router.AreasDone = s.areasDone
+	router.AreaChannels = s.areaChannels
+	commands.RegisterCommands(router, notifier, worldState, commands.CommandHandlers)
+	return router
 }
-
-func (m mudModel) Init() tea.Cmd {
-	return nil
-}
-
-func (m mudModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m, nil
-}
-
-func (m mudModel) View() string {
-	return "Welcome to the MUD!"
-}
-
-func BubbleteaMUD(db *sqlx.DB, server *Server, notifier *notifications.Notifier, areaChannels map[string]chan areas.Action, roomToAreaMap map[string]string, worldState *world_state.WorldState) wish.Middleware {
-	return func(sh ssh.Handler) ssh.Handler {
-		return func(s ssh.Session) {
-			player, err := players.LoginPlayer(s, db)
-			if err != nil || player == nil {
-				fmt.Fprintf(s, "Login failed: %v\n", err)
-				return
-			}
-
-			router := commands.NewCommandRouter()
-			commands.RegisterCommands(router, notifier, worldState, commands.CommandHandlers)
-
-			m := mudModel{
-				db:            db,
-				server:        server,
-				notifier:      notifier,
-				router:        router,
-				player:        player,
-				session:       s,
-				areaChannels:  areaChannels,
-				roomToAreaMap: roomToAreaMap,
-				worldState:    worldState,
-			}
-
-			p := tea.NewProgram(m)
-			if _, err := p.Run(); err != nil {
-				log.Println("Error running program:", err)
-			}
-
-			player.Logout(db)
-		}
-	}
-}
-
-// func BubbleteaMUD(db *sqlx.DB, server *Server, notifier *notifications.Notifier, areaChannels map[string]chan areas.Action, roomToAreaMap map[string]string, worldState *world_state.WorldState) wish.Middleware {
-// 	return func(sh ssh.Handler) ssh.Handler {
-// 		return func(s ssh.Session) {
-// 			router := commands.NewCommandRouter()
-// 			commands.RegisterCommands(router, notifier, worldState, commands.CommandHandlers)
-
-// 			if len(router.Handlers) == 0 {
-// 				fmt.Fprintln(s, "Warning: no commands registered. Exiting...")
-// 				return
-// 			}
-
-// 			server.handleConnection(s, router, db, areaChannels, roomToAreaMap, worldState)
-// 		}
-// 	}
-// }
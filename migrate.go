@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"mud/migrations"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// runMigrate is the migrate subcommand, for moving the schema without starting
+// the server.  Down rolls back one migration unless it is told how many.
+func runMigrate(args []string) error {
+	flags, configPath := newFlagSet("migrate")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		return usage("migrate")
+	}
+
+	_, db, err := openConfigured(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		return migrateUp(db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return usage("migrate")
+			}
+		}
+		ran, err := migrations.Down(db, steps)
+		for _, migration := range ran {
+			fmt.Printf("Rolled back %d %s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrations.GetStatus(db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.Applied {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-45s %s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	default:
+		return usage("migrate")
+	}
+}
+
+// migrateUp brings the schema up to date, the server does this every time it
+// starts.
+func migrateUp(db *sqlx.DB) error {
+	ran, err := migrations.Up(db)
+	for _, migration := range ran {
+		fmt.Printf("Applied %d %s\n", migration.Version, migration.Name)
+	}
+	return err
+}
@@ -0,0 +1,191 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// A Migration moves the schema from the version before it to Version, and
+// Down moves it back again.  Both run inside a transaction along with the
+// schema_migrations bookkeeping, so a migration either happens or it doesn't.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sqlx.Tx) error
+	Down    func(tx *sqlx.Tx) error
+}
+
+// A Status is whether a migration has been applied to the database, and when.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+func createMigrationsTable(db *sqlx.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT,
+			applied_at DATETIME
+		);
+	`)
+	return err
+}
+
+func appliedVersions(db *sqlx.DB) (map[int]time.Time, error) {
+	err := createMigrationsTable(db)
+	if err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations: %v", err)
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		err := rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %v", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// sorted is All in version order, whatever order it was written in.
+func sorted() []Migration {
+	migrations := make([]Migration, len(All))
+	copy(migrations, All)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// Up applies every migration which hasn't been yet, oldest first, and returns
+// the ones it applied.
+func Up(db *sqlx.DB) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, migration := range sorted() {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := run(db, migration, migration.Up, func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				migration.Version, migration.Name, time.Now())
+			return err
+		})
+		if err != nil {
+			return ran, err
+		}
+		ran = append(ran, migration)
+	}
+	return ran, nil
+}
+
+// Down rolls back the latest steps migrations which have been applied, newest
+// first, and returns the ones it rolled back.
+func Down(db *sqlx.DB, steps int) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := sorted()
+	var ran []Migration
+	for i := len(migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := run(db, migration, migration.Down, func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+			return err
+		})
+		if err != nil {
+			return ran, err
+		}
+		ran = append(ran, migration)
+	}
+	return ran, nil
+}
+
+func run(db *sqlx.DB, migration Migration, change func(tx *sqlx.Tx) error, record func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	err = change(tx)
+	if err == nil {
+		err = record(tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error migrating %d %s: %v", migration.Version, migration.Name, err)
+	}
+	return tx.Commit()
+}
+
+// GetStatus lists every migration, and whether it has been applied.
+func GetStatus(db *sqlx.DB) ([]Status, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range sorted() {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+	return statuses, nil
+}
+
+// execAll runs each statement in turn, stopping at the first one which fails.
+func execAll(statements ...string) func(tx *sqlx.Tx) error {
+	return func(tx *sqlx.Tx) error {
+		for _, statement := range statements {
+			_, err := tx.Exec(statement)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumn adds the column unless the table already has it.  Databases made
+// by the old create_tables tool have some of the columns later migrations
+// add, and they need to be able to catch up too.
+func addColumn(tx *sqlx.Tx, table string, column string, definition string) error {
+	var count int
+	err := tx.Get(&count, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE LOWER(name) = LOWER(?)", table, column)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+func dropColumn(tx *sqlx.Tx, table string, column string) error {
+	_, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, column))
+	return err
+}
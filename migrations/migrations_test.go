@@ -0,0 +1,109 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t *testing.T) *sqlx.DB {
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	// every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sqlx.DB, table string) bool {
+	var count int
+	err := db.Get(&count, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func columnExists(t *testing.T, db *sqlx.DB, table string, column string) bool {
+	var count int
+	err := db.Get(&count, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestUpAndDown(t *testing.T) {
+	db := openTestDB(t)
+
+	ran, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up() returned error: %v", err)
+	}
+	if len(ran) != len(All) {
+		t.Errorf("expected %d migrations to run, ran %d", len(All), len(ran))
+	}
+	if !columnExists(t, db, "items", "decays_at") {
+		t.Error("expected the latest migration to have been applied")
+	}
+
+	ran, err = Up(db)
+	if err != nil || len(ran) != 0 {
+		t.Errorf("expected nothing left to run, ran %d, err %v", len(ran), err)
+	}
+
+	ran, err = Down(db, 1)
+	if err != nil {
+		t.Fatalf("Down() returned error: %v", err)
+	}
+	if len(ran) != 1 || ran[0].Version != All[len(All)-1].Version {
+		t.Fatalf("expected the latest migration to be rolled back, got %v", ran)
+	}
+	if columnExists(t, db, "items", "decays_at") {
+		t.Error("expected items.decays_at to be dropped")
+	}
+
+	statuses, err := GetStatus(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.Applied == (status.Version == ran[0].Version) {
+			t.Errorf("migration %d has the wrong status, applied %v", status.Version, status.Applied)
+		}
+	}
+
+	// every migration has to be able to go all the way down and back up again
+	_, err = Down(db, len(All))
+	if err != nil {
+		t.Fatalf("Down() returned error: %v", err)
+	}
+	if tableExists(t, db, "players") {
+		t.Error("expected every table to be dropped")
+	}
+	_, err = Up(db)
+	if err != nil {
+		t.Fatalf("Up() after Down() returned error: %v", err)
+	}
+}
+
+func TestUpCatchesUpOldDatabases(t *testing.T) {
+	db := openTestDB(t)
+	// made by create_tables after accounts were added, before migrations
+	db.MustExec(`CREATE TABLE players (uuid VARCHAR(36) PRIMARY KEY, account_uuid VARCHAR(36), name TEXT)`)
+	db.MustExec(`CREATE TABLE items (uuid VARCHAR(36) PRIMARY KEY, name TEXT, description TEXT, equipment_slots TEXT, container BOOLEAN DEFAULT FALSE)`)
+
+	_, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up() returned error: %v", err)
+	}
+
+	_, err = db.Exec("SELECT account_uuid FROM players")
+	if err != nil {
+		t.Errorf("expected players.account_uuid to survive: %v", err)
+	}
+	_, err = db.Exec("SELECT container, template_uuid FROM items")
+	if err != nil {
+		t.Errorf("expected the missing items columns to be added: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// queryPackages are the packages whose SQL has to match the schema.
+var queryPackages = []string{"players", "items", "mobs", "areas", "audit", "character_classes", "world_state", "commands"}
+
+var sqlStatement = regexp.MustCompile(`(?is)^\s*(SELECT|INSERT|UPDATE|DELETE)\s`)
+
+// fmt verbs stand in for values, ie a uuid or a list of placeholders
+var formatVerb = regexp.MustCompile(`'?%[sdv]'?`)
+
+// notQueries are the string literals which look like SQL but can't be checked
+// on their own, by file and what they start with.  Anything else which doesn't
+// prepare fails the test.
+var notQueries = []struct {
+	file   string
+	prefix string
+}{
+	// menu titles
+	{"players/login-logout.go", "Select a %s Subclass"},
+	{"players/login-logout.go", "Select a %s Subrace"},
+	// the equipment slot columns are filled in at run time
+	{"players/player.go", "UPDATE player_equipments SET %s = ''"},
+	{"players/player.go", "SELECT "},
+	{"players/player.go", "UPDATE player_equipments SET "},
+	// the template columns are filled in at run time
+	{"mobs/template.go", "INSERT INTO mobs (area_uuid, room_uuid, %s)"},
+}
+
+type query struct {
+	position string
+	literal  string
+	sql      string
+}
+
+// isNotQuery checks whether the literal is one of notQueries.
+func (q query) isNotQuery() bool {
+	for _, notQuery := range notQueries {
+		if strings.Contains(filepath.ToSlash(q.position), notQuery.file+":") && strings.HasPrefix(q.literal, notQuery.prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findQueries returns every string literal in the package which looks like a
+// SQL statement.
+func findQueries(t *testing.T, dir string) []query {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		t.Fatalf("error parsing %s: %v", dir, err)
+	}
+
+	var queries []query
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				literal, ok := node.(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					return true
+				}
+				value, err := strconv.Unquote(literal.Value)
+				if err != nil || !sqlStatement.MatchString(value) {
+					return true
+				}
+				queries = append(queries, query{
+					position: fset.Position(literal.Pos()).String(),
+					literal:  value,
+					sql:      formatVerb.ReplaceAllString(value, "?"),
+				})
+				return true
+			})
+		}
+	}
+	return queries
+}
+
+// TestQueriesMatchSchema prepares every query against the migrated schema, so
+// a query using a table or column which doesn't exist fails here rather than
+// in front of a player.  Pieces of queries which are put together at run time
+// don't parse on their own, and are skipped when they're in notQueries.
+func TestQueriesMatchSchema(t *testing.T) {
+	db := openTestDB(t)
+	_, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up() returned error: %v", err)
+	}
+
+	checked, skipped := 0, 0
+	for _, dir := range queryPackages {
+		for _, q := range findQueries(t, filepath.Join("..", dir)) {
+			if q.isNotQuery() {
+				skipped++
+				continue
+			}
+			stmt, err := db.Prepare(q.sql)
+			if err != nil {
+				t.Errorf("%s: %v\n\t%s", q.position, err, strings.Join(strings.Fields(q.sql), " "))
+				continue
+			}
+			stmt.Close()
+			checked++
+		}
+	}
+	if checked == 0 {
+		t.Fatal("expected to find some queries")
+	}
+	// a fragment which has gone away shouldn't be left on the list
+	if skipped != len(notQueries) {
+		t.Errorf("expected to skip the %d literals in notQueries, skipped %d", len(notQueries), skipped)
+	}
+}
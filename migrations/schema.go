@@ -0,0 +1,370 @@
+package migrations
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// All is every change made to the schema, in order.  Once a migration has been
+// released it shouldn't change, add a new one instead.
+var All = []Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		// IF NOT EXISTS so that databases made by the old create_tables tool
+		// can start from here
+		Up: execAll(`
+			CREATE TABLE IF NOT EXISTS players (
+				uuid VARCHAR(36) PRIMARY KEY,
+				character_class TEXT,
+				race TEXT,
+				subrace TEXT,
+				name TEXT,
+				room VARCHAR(36),
+				area VARCHAR(36),
+				hp INTEGER,
+				movement INTEGER,
+				hp_max INTEGER,
+				movement_max INTEGER,
+				color_profile VARCHAR(36),
+				logged_in BOOLEAN DEFAULT FALSE,
+				password VARCHAR(60)
+			);`, `
+			CREATE TABLE IF NOT EXISTS player_abilities (
+				uuid VARCHAR(36) PRIMARY KEY,
+				player_uuid VARCHAR(36),
+				strength INTEGER,
+				dexterity INTEGER,
+				constitution INTEGER,
+				intelligence INTEGER,
+				wisdom INTEGER,
+				charisma INTEGER
+			);`, `
+			CREATE TABLE IF NOT EXISTS player_equipments (
+				uuid VARCHAR(36) PRIMARY KEY,
+				player_uuid VARCHAR(36),
+				Head VARCHAR(36),
+				Neck VARCHAR(36),
+				Chest VARCHAR(36),
+				Arms VARCHAR(36),
+				Hands VARCHAR(36),
+				DominantHand VARCHAR(36),
+				OffHand VARCHAR(36),
+				Legs VARCHAR(36),
+				Feet VARCHAR(36)
+			);`, `
+			CREATE TABLE IF NOT EXISTS color_profiles (
+				uuid VARCHAR(36) PRIMARY KEY,
+				name TEXT,
+				primary_color TEXT,
+				secondary_color TEXT,
+				warning_color TEXT,
+				danger_color TEXT,
+				title_color TEXT,
+				description_color TEXT
+			);`, `
+			CREATE TABLE IF NOT EXISTS areas (
+				uuid VARCHAR(36) PRIMARY KEY,
+				name TEXT,
+				description TEXT
+			);`, `
+			CREATE TABLE IF NOT EXISTS rooms (
+				uuid VARCHAR(36) PRIMARY KEY,
+				area_uuid VARCHAR(36),
+				name TEXT,
+				description TEXT,
+				exit_north VARCHAR(36),
+				exit_south VARCHAR(36),
+				exit_east VARCHAR(36),
+				exit_west VARCHAR(36),
+				exit_up VARCHAR(36),
+				exit_down VARCHAR(36)
+			);`, `
+			CREATE TABLE IF NOT EXISTS item_templates (
+				uuid VARCHAR(36) PRIMARY KEY,
+				name TEXT,
+				description TEXT,
+				equipment_slots TEXT
+			);`, `
+			CREATE TABLE IF NOT EXISTS items (
+				uuid VARCHAR(36) PRIMARY KEY,
+				name TEXT,
+				description TEXT,
+				equipment_slots TEXT
+			);`, `
+			CREATE TABLE IF NOT EXISTS item_locations (
+				item_uuid VARCHAR(36),
+				room_uuid VARCHAR(36) NULL,
+				player_uuid VARCHAR(36) NULL,
+				PRIMARY KEY (item_uuid),
+				FOREIGN KEY (room_uuid) REFERENCES rooms(uuid),
+				FOREIGN KEY (player_uuid) REFERENCES players(uuid)
+			);`, `
+			CREATE TABLE IF NOT EXISTS mobs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				area_uuid VARCHAR(36),
+				room_uuid VARCHAR(36),
+				alignment TEXT,
+				actions TEXT,
+				armor_class INTEGER,
+				armor_description TEXT,
+				challenge_rating FLOAT,
+				charisma INTEGER,
+				charisma_save INTEGER,
+				condition_immunities TEXT,
+				constitution INTEGER,
+				constitution_save INTEGER,
+				damage_immunities TEXT,
+				damage_resistances TEXT,
+				damage_vulnerabilities TEXT,
+				description TEXT,
+				dexterity INTEGER,
+				dexterity_save INTEGER,
+				group_name TEXT,
+				hp INTEGER,
+				hit_dice TEXT,
+				image TEXT,
+				intelligence INTEGER,
+				intelligence_save INTEGER,
+				legendary_description TEXT,
+				name TEXT,
+				perception INTEGER,
+				senses TEXT,
+				size TEXT,
+				slug TEXT,
+				strength INTEGER,
+				strength_save INTEGER,
+				subtype TEXT,
+				type TEXT,
+				wisdom INTEGER,
+				wisdom_save INTEGER
+			);`, `
+			CREATE TABLE IF NOT EXISTS character_races (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT,
+				slug TEXT,
+				size TEXT,
+				description TEXT,
+				asi TEXT,
+				subrace_name TEXT,
+				subrace_slug TEXT,
+				subrace_description TEXT
+			);`, `
+			CREATE TABLE IF NOT EXISTS character_classes (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				hit_dice TEXT,
+				hp_at_first_level INTEGER,
+				hp_modifier TEXT,
+				name TEXT,
+				saving_throw_charisma BOOL,
+				saving_throw_constitution BOOL,
+				saving_throw_dexterity BOOL,
+				saving_throw_intelligence BOOL,
+				saving_throw_strength BOOL,
+				saving_throw_wisdom BOOL,
+				slug TEXT,
+				archetype_slug TEXT,
+				archetype_name TEXT,
+				archetype_description TEXT
+			);`,
+		),
+		Down: execAll(
+			"DROP TABLE IF EXISTS character_classes",
+			"DROP TABLE IF EXISTS character_races",
+			"DROP TABLE IF EXISTS mobs",
+			"DROP TABLE IF EXISTS item_locations",
+			"DROP TABLE IF EXISTS items",
+			"DROP TABLE IF EXISTS item_templates",
+			"DROP TABLE IF EXISTS rooms",
+			"DROP TABLE IF EXISTS areas",
+			"DROP TABLE IF EXISTS color_profiles",
+			"DROP TABLE IF EXISTS player_equipments",
+			"DROP TABLE IF EXISTS player_abilities",
+			"DROP TABLE IF EXISTS players",
+		),
+	},
+	{
+		Version: 2,
+		Name:    "mob templates, behaviors and area resets",
+		Up: func(tx *sqlx.Tx) error {
+			err := addColumn(tx, "mobs", "behaviors", "TEXT DEFAULT ''")
+			if err != nil {
+				return err
+			}
+			err = addColumn(tx, "areas", "reset_interval", "INTEGER DEFAULT 0")
+			if err != nil {
+				return err
+			}
+			return execAll(`
+				CREATE TABLE IF NOT EXISTS mob_templates (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					alignment TEXT,
+					actions TEXT,
+					armor_class INTEGER,
+					armor_description TEXT,
+					challenge_rating FLOAT,
+					charisma INTEGER,
+					charisma_save INTEGER,
+					condition_immunities TEXT,
+					constitution INTEGER,
+					constitution_save INTEGER,
+					damage_immunities TEXT,
+					damage_resistances TEXT,
+					damage_vulnerabilities TEXT,
+					description TEXT,
+					dexterity INTEGER,
+					dexterity_save INTEGER,
+					group_name TEXT,
+					hp INTEGER,
+					hit_dice TEXT,
+					image TEXT,
+					intelligence INTEGER,
+					intelligence_save INTEGER,
+					legendary_description TEXT,
+					name TEXT,
+					perception INTEGER,
+					senses TEXT,
+					size TEXT,
+					slug TEXT,
+					strength INTEGER,
+					strength_save INTEGER,
+					subtype TEXT,
+					type TEXT,
+					wisdom INTEGER,
+					wisdom_save INTEGER,
+					behaviors TEXT DEFAULT ''
+				);`, `
+				CREATE TABLE IF NOT EXISTS area_resets (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					area_uuid VARCHAR(36),
+					room_uuid VARCHAR(36),
+					type TEXT,
+					target TEXT,
+					max INTEGER DEFAULT 1,
+					behaviors TEXT DEFAULT '',
+					FOREIGN KEY (area_uuid) REFERENCES areas(uuid),
+					FOREIGN KEY (room_uuid) REFERENCES rooms(uuid)
+				);`,
+			)(tx)
+		},
+		Down: func(tx *sqlx.Tx) error {
+			err := execAll("DROP TABLE IF EXISTS area_resets", "DROP TABLE IF EXISTS mob_templates")(tx)
+			if err != nil {
+				return err
+			}
+			err = dropColumn(tx, "areas", "reset_interval")
+			if err != nil {
+				return err
+			}
+			return dropColumn(tx, "mobs", "behaviors")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "containers and item templates",
+		Up: func(tx *sqlx.Tx) error {
+			err := addColumn(tx, "items", "container", "BOOLEAN DEFAULT FALSE")
+			if err != nil {
+				return err
+			}
+			err = addColumn(tx, "items", "template_uuid", "VARCHAR(36) NULL")
+			if err != nil {
+				return err
+			}
+			return addColumn(tx, "item_locations", "container_uuid", "VARCHAR(36) NULL")
+		},
+		Down: func(tx *sqlx.Tx) error {
+			err := dropColumn(tx, "item_locations", "container_uuid")
+			if err != nil {
+				return err
+			}
+			err = dropColumn(tx, "items", "template_uuid")
+			if err != nil {
+				return err
+			}
+			return dropColumn(tx, "items", "container")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "accounts and ssh keys",
+		Up: func(tx *sqlx.Tx) error {
+			err := execAll(`
+				CREATE TABLE IF NOT EXISTS accounts (
+					uuid VARCHAR(36) PRIMARY KEY,
+					username TEXT UNIQUE COLLATE NOCASE,
+					password VARCHAR(60),
+					created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+					roles TEXT DEFAULT 'player'
+				);`, `
+				CREATE TABLE IF NOT EXISTS player_keys (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					player_uuid VARCHAR(36),
+					public_key TEXT,
+					fingerprint TEXT,
+					created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+					FOREIGN KEY (player_uuid) REFERENCES players(uuid)
+				);`,
+			)(tx)
+			if err != nil {
+				return err
+			}
+			return addColumn(tx, "players", "account_uuid", "VARCHAR(36)")
+		},
+		Down: func(tx *sqlx.Tx) error {
+			err := dropColumn(tx, "players", "account_uuid")
+			if err != nil {
+				return err
+			}
+			return execAll("DROP TABLE IF EXISTS player_keys", "DROP TABLE IF EXISTS accounts")(tx)
+		},
+	},
+	{
+		Version: 5,
+		Name:    "admin audit log",
+		Up: execAll(`
+			CREATE TABLE IF NOT EXISTS admin_audit (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				account_uuid VARCHAR(36),
+				player_uuid VARCHAR(36),
+				player_name TEXT,
+				command TEXT,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			);`,
+		),
+		Down: execAll("DROP TABLE IF EXISTS admin_audit"),
+	},
+	{
+		Version: 6,
+		Name:    "player aliases",
+		Up: execAll(`
+			CREATE TABLE IF NOT EXISTS player_aliases (
+				player_uuid VARCHAR(36),
+				name TEXT COLLATE NOCASE,
+				expansion TEXT,
+				PRIMARY KEY (player_uuid, name),
+				FOREIGN KEY (player_uuid) REFERENCES players(uuid)
+			);`,
+		),
+		Down: execAll("DROP TABLE IF EXISTS player_aliases"),
+	},
+	{
+		Version: 7,
+		Name:    "player prompts",
+		Up: func(tx *sqlx.Tx) error {
+			return addColumn(tx, "players", "prompt", "TEXT DEFAULT ''")
+		},
+		Down: func(tx *sqlx.Tx) error {
+			return dropColumn(tx, "players", "prompt")
+		},
+	},
+	{
+		Version: 8,
+		Name:    "item decay",
+		Up: func(tx *sqlx.Tx) error {
+			return addColumn(tx, "items", "decays_at", "INTEGER DEFAULT 0")
+		},
+		Down: func(tx *sqlx.Tx) error {
+			return dropColumn(tx, "items", "decays_at")
+		},
+	},
+}
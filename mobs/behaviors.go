@@ -0,0 +1,54 @@
+package mobs
+
+import "strings"
+
+// Behaviors are stored on the mob as a comma separated list, ie
+// "wanderer,aggressive".
+const (
+	// sentinels never leave their room, even when they would otherwise wander or flee
+	BehaviorSentinel = "sentinel"
+	// wanderers roam around the area they were placed in
+	BehaviorWanderer = "wanderer"
+	// aggressive mobs attack any player they see
+	BehaviorAggressive = "aggressive"
+	// wimpy mobs run away when they are badly hurt
+	BehaviorWimpy = "wimpy"
+)
+
+// below this fraction of their max hp, wimpy mobs try to flee
+const WimpyHPFraction = 0.25
+
+func ParseBehaviors(list string) []string {
+	var behaviors []string
+	for _, behavior := range strings.Split(list, ",") {
+		behavior = strings.ToLower(strings.TrimSpace(behavior))
+		if behavior != "" {
+			behaviors = append(behaviors, behavior)
+		}
+	}
+	return behaviors
+}
+
+func (mob *Mob) GetBehaviors() []string {
+	return ParseBehaviors(mob.Behaviors)
+}
+
+func (mob *Mob) HasBehavior(behavior string) bool {
+	for _, b := range mob.GetBehaviors() {
+		if b == behavior {
+			return true
+		}
+	}
+	return false
+}
+
+func (mob *Mob) CanMove() bool {
+	return !mob.HasBehavior(BehaviorSentinel)
+}
+
+func (mob *Mob) WantsToFlee() bool {
+	if !mob.HasBehavior(BehaviorWimpy) || mob.HP <= 0 {
+		return false
+	}
+	return float64(mob.HP) <= float64(mob.MaxHP)*WimpyHPFraction
+}
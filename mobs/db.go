@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/mitchellh/mapstructure"
@@ -12,7 +15,7 @@ import (
 // this object represents how mobs are stored in the database.  the fields should
 // map to a Mob.
 type MobDB struct {
-	ID                    int64   `db:"id" mapstructure:"db"`
+	ID                    int64   `db:"id" mapstructure:"id"`
 	AreaUUID              string  `db:"area_uuid" mapstructure:"area_uuid"`
 	RoomUUID              string  `db:"room_uuid" mapstructure:"room_uuid"`
 	Alignment             string  `db:"alignment" mapstructure:"alignment"`
@@ -32,7 +35,6 @@ type MobDB struct {
 	DexteritySave         int32   `db:"dexterity_save" mapstructure:"dexterity_save"`
 	Group                 string  `db:"group_name" mapstructure:"group_name"`
 	HP                    int32   `db:"hp" mapstructure:"hp"`
-	MaxHP                 int32   `db:"hp" mapstructure:"hp"`
 	HitDice               string  `db:"hit_dice" mapstructure:"hit_dice"`
 	Intelligence          int32   `db:"intelligence" mapstructure:"intelligence"`
 	IntelligenceSave      int32   `db:"intelligence_save" mapstructure:"intelligence_save"`
@@ -48,6 +50,7 @@ type MobDB struct {
 	Type                  string  `db:"type" mapstructure:"type"`
 	Wisdom                int32   `db:"wisdom" mapstructure:"wisdom"`
 	WisdomSave            int32   `db:"wisdom_save" mapstructure:"wisdom_save"`
+	Behaviors             string  `db:"behaviors" mapstructure:"behaviors"`
 	Actions               string  `db:"actions" mapstructure:"actions"`
 }
 
@@ -58,78 +61,113 @@ func GetMobsInRoom(db *sqlx.DB, roomUUID string) ([]*Mob, error) {
 		log.Fatalf("failed to fetch mobs from tabel: %v", err)
 	}
 
+	defer rows.Close()
+
 	for rows.Next() {
-		result := make(map[string]interface{})
-		err = rows.MapScan(result)
+		mob, err := scanMob(rows)
 		if err != nil {
-			log.Fatalf("failed to scan row: %v", err)
+			log.Fatalf("%v", err)
 		}
+		mobs = append(mobs, mob)
+	}
 
-		var mobDb MobDB
-		err = mapstructure.Decode(result, &mobDb)
-		if err != nil {
-			log.Fatalf("failed to decode row: %v", err)
-		}
+	return mobs, nil
 
-		fmt.Printf("Actions: %s\n", mobDb.Actions)
-		// TODO I have no idea what I meant to do here...
-		fmt.Printf("Type of Action: foo\n")
+}
 
-		var actions []Action
-		err = json.Unmarshal([]byte(mobDb.Actions), &actions)
-		if err != nil {
-			log.Fatalf("error unmarshaling actions: %v", err)
-		}
+func scanMob(rows *sqlx.Rows) (*Mob, error) {
+	result := make(map[string]interface{})
+	err := rows.MapScan(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %v", err)
+	}
 
-		var mobActions []*Action
-		for idx := range actions {
-			mobActions = append(mobActions, &actions[idx])
-		}
+	var mobDb MobDB
+	err = mapstructure.Decode(result, &mobDb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode row: %v", err)
+	}
 
-		mob := Mob{
-			ID:                    mobDb.ID,
-			AreaUUID:              mobDb.AreaUUID,
-			RoomUUID:              mobDb.RoomUUID,
-			Alignment:             mobDb.Alignment,
-			ArmorClass:            mobDb.ArmorClass,
-			ArmorDescription:      mobDb.ArmorDescription,
-			ChallengeRating:       mobDb.ChallengeRating,
-			Charisma:              mobDb.Charisma,
-			CharismaSave:          mobDb.CharismaSave,
-			ConditionImmunities:   mobDb.ConditionImmunities,
-			Constitution:          mobDb.Constitution,
-			ConstitutionSave:      mobDb.ConstitutionSave,
-			DamageImmunities:      mobDb.DamageImmunities,
-			DamageResistances:     mobDb.DamageResistances,
-			DamageVulnerabilities: mobDb.DamageVulnerabilities,
-			Description:           mobDb.Description,
-			Dexterity:             mobDb.Dexterity,
-			DexteritySave:         mobDb.DexteritySave,
-			Group:                 mobDb.Group,
-			HP:                    mobDb.HP,
-			MaxHP:                 mobDb.MaxHP,
-			HitDice:               mobDb.HitDice,
-			Intelligence:          mobDb.Intelligence,
-			IntelligenceSave:      mobDb.IntelligenceSave,
-			LegendaryDescription:  mobDb.LegendaryDescription,
-			Name:                  mobDb.Name,
-			Perception:            mobDb.Perception,
-			Senses:                mobDb.Senses,
-			Size:                  mobDb.Size,
-			Slug:                  mobDb.Slug,
-			Strength:              mobDb.Strength,
-			StrengthSave:          mobDb.StrengthSave,
-			Subtype:               mobDb.Subtype,
-			Type:                  mobDb.Type,
-			Wisdom:                mobDb.Wisdom,
-			WisdomSave:            mobDb.WisdomSave,
-			// TODO figure out what to do here.
-			// Actions:               mobDb.Actions,
-		}
+	var actions []Action
+	err = json.Unmarshal([]byte(mobDb.Actions), &actions)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling actions: %v", err)
+	}
 
-		mobs = append(mobs, &mob)
+	var mobActions []*Action
+	for idx := range actions {
+		mobActions = append(mobActions, &actions[idx])
 	}
 
-	return mobs, nil
+	mob := Mob{
+		ID:                    mobDb.ID,
+		AreaUUID:              mobDb.AreaUUID,
+		RoomUUID:              mobDb.RoomUUID,
+		Alignment:             mobDb.Alignment,
+		ArmorClass:            mobDb.ArmorClass,
+		ArmorDescription:      mobDb.ArmorDescription,
+		ChallengeRating:       mobDb.ChallengeRating,
+		Charisma:              mobDb.Charisma,
+		CharismaSave:          mobDb.CharismaSave,
+		ConditionImmunities:   mobDb.ConditionImmunities,
+		Constitution:          mobDb.Constitution,
+		ConstitutionSave:      mobDb.ConstitutionSave,
+		DamageImmunities:      mobDb.DamageImmunities,
+		DamageResistances:     mobDb.DamageResistances,
+		DamageVulnerabilities: mobDb.DamageVulnerabilities,
+		Description:           mobDb.Description,
+		Dexterity:             mobDb.Dexterity,
+		DexteritySave:         mobDb.DexteritySave,
+		Group:                 mobDb.Group,
+		HP:                    mobDb.HP,
+		MaxHP:                 mobDb.HP,
+		HitDice:               mobDb.HitDice,
+		Intelligence:          mobDb.Intelligence,
+		IntelligenceSave:      mobDb.IntelligenceSave,
+		LegendaryDescription:  mobDb.LegendaryDescription,
+		Name:                  mobDb.Name,
+		Perception:            mobDb.Perception,
+		Senses:                mobDb.Senses,
+		Size:                  mobDb.Size,
+		Slug:                  mobDb.Slug,
+		Strength:              mobDb.Strength,
+		StrengthSave:          mobDb.StrengthSave,
+		Subtype:               mobDb.Subtype,
+		Type:                  mobDb.Type,
+		Wisdom:                mobDb.Wisdom,
+		WisdomSave:            mobDb.WisdomSave,
+		Behaviors:             mobDb.Behaviors,
+		RNG:                   rand.New(rand.NewSource(time.Now().UnixNano())),
+		Actions:               mobActions,
+	}
+
+	return &mob, nil
+}
 
+func DeleteMob(db *sqlx.DB, id int64) error {
+	_, err := db.Exec("DELETE FROM mobs WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete mob %d: %v", id, err)
+	}
+	return nil
+}
+
+func (mob *Mob) SetLocation(db *sqlx.DB, areaUUID string, roomUUID string) error {
+	_, err := db.Exec("UPDATE mobs SET area_uuid = ?, room_uuid = ? WHERE id = ?", areaUUID, roomUUID, mob.ID)
+	if err != nil {
+		return fmt.Errorf("failed to move mob %d: %v", mob.ID, err)
+	}
+	mob.AreaUUID = areaUUID
+	mob.RoomUUID = roomUUID
+	return nil
+}
+
+func (mob *Mob) SetBehaviors(db *sqlx.DB, behaviors []string) error {
+	joined := strings.Join(behaviors, ",")
+	_, err := db.Exec("UPDATE mobs SET behaviors = ? WHERE id = ?", joined, mob.ID)
+	if err != nil {
+		return fmt.Errorf("failed to set behaviors for mob %d: %v", mob.ID, err)
+	}
+	mob.Behaviors = joined
+	return nil
 }
@@ -1,8 +1,8 @@
 package mobs
 
 import (
-	"fmt"
 	"log"
+	"mud/combat"
 	"mud/utilities"
 	"regexp"
 	"strconv"
@@ -20,7 +20,7 @@ type Action struct {
 }
 
 type Mob struct {
-	ID                    int64   `db:"id" mapstructure:"db"`
+	ID                    int64   `db:"id" mapstructure:"id"`
 	AreaUUID              string  `db:"area_uuid" mapstructure:"area_uuid"`
 	RoomUUID              string  `db:"room_uuid" mapstructure:"room_uuid"`
 	Alignment             string  `db:"alignment" mapstructure:"alignment"`
@@ -40,7 +40,7 @@ type Mob struct {
 	DexteritySave         int32   `db:"dexterity_save" mapstructure:"dexterity_save"`
 	Group                 string  `db:"group_name" mapstructure:"group_name"`
 	HP                    int32   `db:"hp" mapstructure:"hp"`
-	MaxHP                 int32   `db:"hp" mapstructure:"hp"`
+	MaxHP                 int32   `db:"-" mapstructure:"-"` // mobs are stored at full health, so it is the hp they load with
 	HitDice               string  `db:"hit_dice" mapstructure:"hit_dice"`
 	Intelligence          int32   `db:"intelligence" mapstructure:"intelligence"`
 	IntelligenceSave      int32   `db:"intelligence_save" mapstructure:"intelligence_save"`
@@ -56,6 +56,7 @@ type Mob struct {
 	Type                  string  `db:"type" mapstructure:"type"`
 	Wisdom                int32   `db:"wisdom" mapstructure:"wisdom"`
 	WisdomSave            int32   `db:"wisdom_save" mapstructure:"wisdom_save"`
+	Behaviors             string  `db:"behaviors" mapstructure:"behaviors"`
 	RNG                   RNG     `db:"-"`
 	Actions               []*Action
 }
@@ -64,6 +65,39 @@ type RNG interface {
 	Intn(n int) int
 }
 
+func (mob *Mob) GetName() string {
+	return mob.Name
+}
+
+func (mob *Mob) GetHP() int32 {
+	return mob.HP
+}
+
+func (mob *Mob) GetArmorClass() int32 {
+	return mob.ArmorClass
+}
+
+func (mob *Mob) GetAbilities() combat.Abilities {
+	return mob
+}
+
+func (mob *Mob) GetAttackModifier(weaponType string) int32 {
+	if weaponType == "ranged" {
+		return mob.GetDexterityModifier()
+	} else if weaponType == "melee" {
+		return mob.GetStrengthModifier()
+	}
+	return 0
+}
+
+func (mob *Mob) GetStrengthModifier() int32 {
+	return utilities.CalculateAbilityModifier(mob.Strength)
+}
+
+func (mob *Mob) GetDexterityModifier() int32 {
+	return utilities.CalculateAbilityModifier(mob.Dexterity)
+}
+
 func (mob *Mob) RollHitDice() int32 {
 	// TODO: add other modifiers from feats, spells,
 	// and other effects.
@@ -76,113 +110,160 @@ func (mob *Mob) RollInitiative() int32 {
 	return utilities.DiceRoll("1d20") + int32(mob.DexteritySave)
 }
 
-func (mob *Mob) AttackRoll(opponent *Opponent, attack *Action) bool {
+// AttackRoll rolls to hit the opponent with the attack.  It returns whether
+// the attack hit, and whether it was a critical hit.
+func (mob *Mob) AttackRoll(opponent combat.Opponent, attack *Action) (bool, bool) {
 	// TODO: add/figure out attack bonus ranged vs melee etc
-	diceRoll := utilities.DiceRoll("1d20")
+	diceRoll := int32(mob.RNG.Intn(20) + 1)
+	armorClass := opponent.GetArmorClass()
 	if strings.ToLower(attack.Name) == "multiattack" {
-		if diceRoll >= opponent.ArmorClass {
-			fmt.Printf("MultiAttack! \"%s\"\nDice Roll: %d, Target AC: %d - SUCCESS\n", attack.Description, diceRoll, opponent.ArmorClass)
-			return true
-		} else {
-			fmt.Printf("MultiAttack! Dice Roll: %d, Target AC: %d - FAILED\n", diceRoll, opponent.ArmorClass)
-			return false
-		}
+		return diceRoll >= armorClass, false
 	}
-	attackBonus := attack.AttackBonus
-	hit := diceRoll+attackBonus >= opponent.ArmorClass
-	fmt.Printf("Dice Roll: %d, Mob Attack Bonus: %d, Target AC: %d", diceRoll, attackBonus, opponent.ArmorClass)
 	if diceRoll == 20 {
-		fmt.Println(" - Automatic HIT!")
-		return true
+		return true, true
 	} else if diceRoll == 1 {
-		fmt.Println(" - Automatic MISS!")
-		return false
-	}
-	if hit {
-		fmt.Printf(" - HIT\n")
-	} else {
-		fmt.Printf(" - MISS\n")
+		return false, false
 	}
-	return hit
+	return diceRoll+attack.AttackBonus >= armorClass, false
 }
 
-type Opponent struct {
-	ArmorClass int32
-}
-
-func (mob *Mob) ExecuteRegularAttack(opponent *Opponent, attack *Action) {
-	if mob.AttackRoll(opponent, attack) {
-		// Regular expression to match damage types:
-		// re := regexp.MustCompile(`(\w+) damage`)
-		// re := regexp.MustCompile(`\(\d+d\d+\+\d+\) (\w+)`)
-		re := regexp.MustCompile(`\(\d+d\d+(\s*\+\s*\d+)?\) (\w+)`)
+// ExecuteRegularAttack rolls to hit the opponent with the attack and, if it
+// lands, rolls the damage for each damage type named in the attack's
+// description.  Nothing is taken off the opponent's hit points here; pass the
+// result to combat.ApplyAttackResult for that.
+func (mob *Mob) ExecuteRegularAttack(opponent combat.Opponent, attack *Action) *combat.AttackResult {
+	result := &combat.AttackResult{AttackerName: mob.Name, AttackName: attack.Name}
+	result.Hit, result.Critical = mob.AttackRoll(opponent, attack)
+	if !result.Hit {
+		return result
+	}
 
-		// Find all damage types in the desc field:
-		matches := re.FindAllStringSubmatch(attack.Description, -1)
+	// Regular expression to match damage types:
+	// re := regexp.MustCompile(`(\w+) damage`)
+	// re := regexp.MustCompile(`\(\d+d\d+\+\d+\) (\w+)`)
+	re := regexp.MustCompile(`\(\d+d\d+(\s*\+\s*\d+)?\) (\w+)`)
 
-		// Split the damage_dice field to get the dice for each damage type:
-		damageDice := strings.Split(attack.DamageDice, "+")
+	// Find all damage types in the desc field:
+	matches := re.FindAllStringSubmatch(attack.Description, -1)
 
-		// Create a slice to hold the resulting damage instructions:
-		result := make([][]string, len(matches))
+	// Split the damage_dice field to get the dice for each damage type:
+	damageDice := strings.Split(attack.DamageDice, "+")
 
-		// Iterate over the matches and dice and add them to the result slice:
-		if len(matches) == 1 {
-			result[0] = []string{matches[0][len(matches[0])-1], strconv.Itoa(int(utilities.DiceRoll(attack.DamageDice)))}
-		} else {
-			for i, match := range matches {
-				result[i] = []string{match[len(match)-1], strconv.Itoa(int(utilities.DiceRoll(damageDice[i])))}
+	// Iterate over the matches and dice and add them to the result:
+	if len(matches) == 0 {
+		result.Damage = append(result.Damage, combat.DamageRoll{Amount: combat.RollDamageDice(attack.DamageDice, result.Critical)})
+	} else if len(matches) == 1 {
+		result.Damage = append(result.Damage, combat.DamageRoll{
+			Type:   matches[0][len(matches[0])-1],
+			Amount: combat.RollDamageDice(attack.DamageDice, result.Critical),
+		})
+	} else {
+		for i, match := range matches {
+			dice := damageDice[len(damageDice)-1]
+			if i < len(damageDice) {
+				dice = damageDice[i]
 			}
+			result.Damage = append(result.Damage, combat.DamageRoll{
+				Type:   match[len(match)-1],
+				Amount: combat.RollDamageDice(dice, result.Critical),
+			})
 		}
-		// attackDamage := dice.DiceRoll(attack.GetDamageDice())
-		// fmt.Printf("%s attacks! %s does %d damage\n", mob.GetName(), attack.GetName(), attackDamage)
-		// need to decrease the opponent's health.
-		if len(result) == 1 {
-			fmt.Printf("%s's %s does %s %s damage\n", mob.Name, attack.Name, result[0][1], result[0][0])
-		} else if len(result) == 2 {
-			var damageResults []string
-			for idx := range result {
-				damageResults = append(damageResults, fmt.Sprintf("%s %s damage", result[idx][1], result[idx][0]))
-			}
-			fmt.Printf("%s's %s does %s\n", mob.Name, attack.Name, strings.Join(damageResults, " and "))
-		} else if len(result) > 2 {
-			var damageResults []string
-			for idx := range result {
-				damageResults = append(damageResults, fmt.Sprintf("%s %s damage", result[idx][1], result[idx][0]))
-			}
-			last := damageResults[len(damageResults)-1]
-			damageResults = damageResults[:len(damageResults)-1]
-
-			fmt.Printf("%s's %s does %s\n", mob.Name, attack.Name, strings.Join(damageResults, ", ")+", and "+last)
-
-		} else {
-			fmt.Println("whoops!")
-		}
-		// TODO Adam need to decrement Opponent hitpoints
-		fmt.Printf("yo!")
 	}
+	return result
 }
 
-func (mob *Mob) ExecuteAction(opponent *Opponent) {
+// ExecuteAction picks one of the mob's actions and uses it against the
+// opponent, returning the result of every attack it made.
+func (mob *Mob) ExecuteAction(opponent combat.Opponent) []*combat.AttackResult {
 	// Mob has a bunch of actions, need to pick one
 	// "regular" actions are ones which have DamageDice - put those into a bucket
 	actions := mob.Actions
 	regularAttacks := getRegularAttacks(actions)
 	multiAttack := getMultiAttack(actions)
 
-	if multiAttack != nil && mob.AttackRoll(opponent, multiAttack) {
-		attacks := getAttacksForMultiAttack(multiAttack.Description, regularAttacks)
-		for idx := range attacks {
-			mob.ExecuteRegularAttack(opponent, attacks[idx])
+	if len(regularAttacks) == 0 {
+		return nil
+	}
+
+	var results []*combat.AttackResult
+	if multiAttack != nil {
+		if hit, _ := mob.AttackRoll(opponent, multiAttack); hit {
+			attacks := getAttacksForMultiAttack(multiAttack.Description, regularAttacks)
+			for idx := range attacks {
+				results = append(results, mob.ExecuteRegularAttack(opponent, attacks[idx]))
+			}
+			return results
+		}
+	}
+
+	// if the mob fails a roll to make a multiattack OR if the mob
+	// does not have multiattack capability, select a regular attack at
+	// random and execute it
+	index := mob.RNG.Intn(len(regularAttacks))
+	attack := regularAttacks[index]
+	return append(results, mob.ExecuteRegularAttack(opponent, attack))
+}
+
+func (mob *Mob) IsImmuneTo(damage combat.DamageRoll) bool {
+	return damageTypeListed(mob.DamageImmunities, damage)
+}
+
+func (mob *Mob) IsResistantTo(damage combat.DamageRoll) bool {
+	return damageTypeListed(mob.DamageResistances, damage)
+}
+
+func (mob *Mob) IsVulnerableTo(damage combat.DamageRoll) bool {
+	return damageTypeListed(mob.DamageVulnerabilities, damage)
+}
+
+func (mob *Mob) TakeDamage(amount int32) {
+	mob.HP -= amount
+}
+
+// knownDamageTypes are the types of damage the monster imports name.
+var knownDamageTypes = []string{
+	"acid", "bludgeoning", "cold", "fire", "force", "lightning", "necrotic",
+	"piercing", "poison", "psychic", "radiant", "slashing", "thunder",
+}
+
+// the damage_* fields come straight from the monster imports, and look like
+// "cold; bludgeoning, piercing, and slashing from nonmagical attacks".  Each
+// clause between the ;s lists damage types, and a clause about nonmagical
+// attacks only covers damage which isn't magical.  Nothing is silvered or
+// adamantine yet, so "that aren't silvered" and the like always apply.
+func damageTypeListed(damageTypes string, damage combat.DamageRoll) bool {
+	if damage.Type == "" {
+		return false
+	}
+	for _, clause := range strings.Split(strings.ToLower(damageTypes), ";") {
+		nonmagical := strings.Contains(clause, "nonmagical") || strings.Contains(clause, "non magical") || strings.Contains(clause, "non-magical")
+		if nonmagical && damage.Magical {
+			continue
+		}
+		for _, listed := range clauseDamageTypes(clause) {
+			if listed == strings.ToLower(damage.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clauseDamageTypes picks the damage types out of one clause, word by word, so
+// that the rest of the clause can't be mistaken for one.
+func clauseDamageTypes(clause string) []string {
+	var listed []string
+	words := strings.FieldsFunc(clause, func(r rune) bool {
+		return r < 'a' || r > 'z'
+	})
+	for _, word := range words {
+		for _, damageType := range knownDamageTypes {
+			if word == damageType {
+				listed = append(listed, word)
+			}
 		}
-	} else {
-		// if the mob fails a roll to make a multiattack OR if the mob
-		// does not have multiattack capability, select a regular attack at
-		// random and execute it
-		index := mob.RNG.Intn(len(regularAttacks))
-		attack := regularAttacks[index]
-		mob.ExecuteRegularAttack(opponent, attack)
 	}
+	return listed
 }
 
 func getMultiAttack(mobActions []*Action) *Action {
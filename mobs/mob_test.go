@@ -1,8 +1,13 @@
 package mobs_test
 
 import (
+	"mud/combat"
+	"mud/migrations"
 	"mud/mobs"
 	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 // TODO what are these mocks about? I forget
@@ -56,29 +61,59 @@ func (a MockAction) SetDescription(desc string) {
 	a.Description = desc
 }
 
+// MockRNG always rolls the same, IntnValue 0 is a 1 on the d20 and 9 a 10.
 type MockRNG struct {
 	IntnValue int
 }
 
 func (r *MockRNG) Intn(n int) int {
+	if r.IntnValue >= n {
+		return n - 1
+	}
 	return r.IntnValue
 }
 
 func TestExecuteAction(t *testing.T) {
+	rng := &MockRNG{}
 	mob := &mobs.Mob{
 		Name: "Monster",
 		Actions: []*mobs.Action{
 			{Name: "Action1", Description: "The description for action 1 (1d6+1) bludgeoning damage", DamageDice: "1d6+1", AttackBonus: 5, DamageBonus: 5},
 		},
-		RNG: &MockRNG{IntnValue: 0},
+		RNG: rng,
+	}
+	opponent := &MockOpponent{}
+
+	// a 10 and the attack bonus beats the opponent's armor class
+	rng.IntnValue = 9
+	results := mob.ExecuteAction(opponent)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 attack result, got %d", len(results))
+	}
+	if !results[0].Hit || results[0].Critical {
+		t.Errorf("expected a hit which isn't critical, got %+v", results[0])
+	}
+	if len(results[0].Damage) != 1 || results[0].Damage[0].Type != "bludgeoning" || results[0].Damage[0].Amount < 2 {
+		t.Errorf("expected a single bludgeoning damage roll, got %v", results[0].Damage)
 	}
 
-	opponent := &mobs.Opponent{}
+	// a 1 always misses, whatever the bonus
+	rng.IntnValue = 0
+	results = mob.ExecuteAction(opponent)
+	if len(results) != 1 || results[0].Hit || len(results[0].Damage) != 0 {
+		t.Errorf("expected a miss without damage, got %+v", results[0])
+	}
 
-	mob.ExecuteAction(opponent)
+	// a 20 is a critical hit
+	rng.IntnValue = 19
+	results = mob.ExecuteAction(opponent)
+	if len(results) != 1 || !results[0].Hit || !results[0].Critical {
+		t.Errorf("expected a critical hit, got %+v", results[0])
+	}
 }
 
 func TestExecuteActionMultiAttack(t *testing.T) {
+	rng := &MockRNG{}
 	mob := &mobs.Mob{
 		Name: "Monster",
 		Actions: []*mobs.Action{
@@ -86,12 +121,27 @@ func TestExecuteActionMultiAttack(t *testing.T) {
 			&mobs.Action{Name: "Action2", Description: "The description for action 2 includs (2d4) piercing", DamageDice: "1d8", AttackBonus: 3, DamageBonus: 6},
 			&mobs.Action{Name: "Multiattack", Description: "The Monster makes one Action1 and two Action2 attacks", DamageDice: "", AttackBonus: 0, DamageBonus: 0},
 		},
-		RNG: &MockRNG{IntnValue: 0},
+		RNG: rng,
 	}
+	opponent := &MockOpponent{}
 
-	opponent := &mobs.Opponent{}
+	rng.IntnValue = 9
+	results := mob.ExecuteAction(opponent)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 attack results for a multiattack, got %d", len(results))
+	}
+	for i, want := range []string{"Action1", "Action2", "Action2"} {
+		if results[i].AttackName != want || !results[i].Hit {
+			t.Errorf("expected attack %d to be a hit with %s, got %+v", i, want, results[i])
+		}
+	}
 
-	mob.ExecuteAction(opponent)
+	// failing the multiattack roll falls back to a single attack
+	rng.IntnValue = 0
+	results = mob.ExecuteAction(opponent)
+	if len(results) != 1 || results[0].Hit {
+		t.Errorf("expected a single missed attack, got %d results", len(results))
+	}
 }
 
 func TestExecuteActionWithMultipleDamageTypes(t *testing.T) {
@@ -100,10 +150,101 @@ func TestExecuteActionWithMultipleDamageTypes(t *testing.T) {
 		Actions: []*mobs.Action{
 			&mobs.Action{Name: "Action2", Description: "The description for action 2 (1d4) bludgeoning damage something (2d6) piercing.", DamageDice: "1d8+1d4", AttackBonus: 3, DamageBonus: 6},
 		},
-		RNG: &MockRNG{},
+		RNG: &MockRNG{IntnValue: 9},
+	}
+
+	opponent := &MockOpponent{}
+
+	results := mob.ExecuteAction(opponent)
+	if len(results) != 1 || !results[0].Hit {
+		t.Fatalf("expected 1 attack which hit, got %d", len(results))
+	}
+	damage := results[0].Damage
+	if len(damage) != 2 || damage[0].Type != "bludgeoning" || damage[1].Type != "piercing" {
+		t.Errorf("expected bludgeoning and piercing damage rolls, got %v", damage)
+	}
+}
+
+func TestDamageModifiers(t *testing.T) {
+	mob := &mobs.Mob{
+		DamageImmunities:      "poison",
+		DamageResistances:     "cold; bludgeoning, piercing, and slashing from nonmagical attacks that aren't silvered",
+		DamageVulnerabilities: "fire",
+	}
+
+	testCases := []struct {
+		damage                        combat.DamageRoll
+		immune, resistant, vulnerable bool
+	}{
+		{damage: combat.DamageRoll{Type: "poison"}, immune: true},
+		{damage: combat.DamageRoll{Type: "slashing"}, resistant: true},
+		{damage: combat.DamageRoll{Type: "slashing", Magical: true}},
+		{damage: combat.DamageRoll{Type: "cold", Magical: true}, resistant: true},
+		{damage: combat.DamageRoll{Type: "fire"}, vulnerable: true},
+		{damage: combat.DamageRoll{Type: "acid"}},
+		{damage: combat.DamageRoll{}},
+	}
+
+	for _, tc := range testCases {
+		if mob.IsImmuneTo(tc.damage) != tc.immune {
+			t.Errorf("IsImmuneTo(%+v): expected %t", tc.damage, tc.immune)
+		}
+		if mob.IsResistantTo(tc.damage) != tc.resistant {
+			t.Errorf("IsResistantTo(%+v): expected %t", tc.damage, tc.resistant)
+		}
+		if mob.IsVulnerableTo(tc.damage) != tc.vulnerable {
+			t.Errorf("IsVulnerableTo(%+v): expected %t", tc.damage, tc.vulnerable)
+		}
+	}
+}
+
+func TestBehaviors(t *testing.T) {
+	mob := &mobs.Mob{Behaviors: "Wanderer, aggressive,wimpy", HP: 30, MaxHP: 100}
+
+	if !mob.HasBehavior(mobs.BehaviorWanderer) || !mob.HasBehavior(mobs.BehaviorAggressive) {
+		t.Errorf("expected mob to be a wanderer and aggressive, got %v", mob.GetBehaviors())
+	}
+	if !mob.CanMove() {
+		t.Errorf("expected a mob without sentinel to be able to move")
+	}
+	if mob.WantsToFlee() {
+		t.Errorf("expected a wimpy mob at 30/100 hp not to flee")
+	}
+
+	mob.HP = 25
+	if !mob.WantsToFlee() {
+		t.Errorf("expected a wimpy mob at 25/100 hp to flee")
+	}
+
+	sentinel := &mobs.Mob{Behaviors: "sentinel,wanderer"}
+	if sentinel.CanMove() {
+		t.Errorf("expected a sentinel not to move")
+	}
+}
+
+func TestWimpyMobsFromTheDatabaseFlee(t *testing.T) {
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	// every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
 	}
+	db.MustExec("INSERT INTO mob_templates (slug, name, hp, actions, behaviors) VALUES ('rat', 'a rat', 20, '[]', 'wimpy')")
 
-	opponent := &mobs.Opponent{}
+	mob, err := mobs.NewMobFromTemplate(db, "rat", "area", "cellar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mob.HP != 20 || mob.MaxHP != 20 {
+		t.Fatalf("expected the rat to load with 20/20 hp, got %d/%d", mob.HP, mob.MaxHP)
+	}
+	if mob.WantsToFlee() {
+		t.Errorf("expected a rat at full health not to flee")
+	}
 
-	mob.ExecuteAction(opponent)
+	mob.HP = 4
+	if !mob.WantsToFlee() {
+		t.Errorf("expected a rat at 4/20 hp to flee")
+	}
 }
@@ -0,0 +1,79 @@
+package mobs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// the columns a mob copies from its template, everything except where it lives.
+var templateColumns = []string{
+	"alignment", "actions", "armor_class", "armor_description", "challenge_rating",
+	"charisma", "charisma_save", "condition_immunities", "constitution", "constitution_save",
+	"damage_immunities", "damage_resistances", "damage_vulnerabilities", "description",
+	"dexterity", "dexterity_save", "group_name", "hp", "hit_dice", "intelligence",
+	"intelligence_save", "legendary_description", "name", "perception", "senses", "size",
+	"slug", "strength", "strength_save", "subtype", "type", "wisdom", "wisdom_save", "behaviors",
+}
+
+// NewMobFromTemplate creates a fresh copy of the mob with the given slug in the
+// room, using the stats stored in mob_templates.
+func NewMobFromTemplate(db *sqlx.DB, slug string, areaUUID string, roomUUID string) (*Mob, error) {
+	columns := strings.Join(templateColumns, ", ")
+	query := fmt.Sprintf(`INSERT INTO mobs (area_uuid, room_uuid, %s)
+				SELECT ?, ?, %s
+				FROM mob_templates
+				WHERE slug = ?
+				LIMIT 1`, columns, columns)
+	result, err := db.Exec(query, areaUUID, roomUUID, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert mob: %v", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if inserted == 0 {
+		return nil, fmt.Errorf("no mob template with slug %s", slug)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return GetMob(db, id)
+}
+
+func GetMob(db *sqlx.DB, id int64) (*Mob, error) {
+	rows, err := db.Queryx("SELECT * FROM mobs WHERE id = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch mob %d: %v", id, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, fmt.Errorf("mob %d does not exist", id)
+	}
+	return scanMob(rows)
+}
+
+func CountMobsInRoom(db *sqlx.DB, slug string, roomUUID string) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM mobs WHERE slug = ? AND room_uuid = ?", slug, roomUUID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count mobs: %v", err)
+	}
+	return count, nil
+}
+
+// CountMobsInArea counts the mobs with the slug anywhere in the area.
+func CountMobsInArea(db *sqlx.DB, slug string, areaUUID string) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM mobs WHERE slug = ? AND area_uuid = ?", slug, areaUUID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count mobs: %v", err)
+	}
+	return count, nil
+}
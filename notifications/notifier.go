@@ -1,42 +1,44 @@
 package notifications
 
 import (
-	"fmt"
 	"mud/display"
 	"mud/players"
+	"mud/sessions"
 )
 
 type Notifier struct {
-	Players map[string]*players.Player
+	Registry *sessions.Registry
 }
 
-func NewNotifier(connections map[string]*players.Player) *Notifier {
-	return &Notifier{Players: connections}
+func NewNotifier(registry *sessions.Registry) *Notifier {
+	return &Notifier{Registry: registry}
 }
 
 func (n *Notifier) NotifyRoom(roomID string, playerUUID string, message string) {
-	fmt.Println("Notifying room", roomID, "with message", message)
 	var playersInRoom []*players.Player
-	for _, player := range n.Players {
-		if player.RoomUUID == roomID && player.UUID != playerUUID {
+	for _, player := range n.Registry.InRoom(roomID) {
+		if player.UUID != playerUUID {
 			playersInRoom = append(playersInRoom, player)
 		}
 	}
 	for _, player := range playersInRoom {
 		display.PrintWithColor(player, message, "primary")
-		display.PrintWithColor(player, fmt.Sprintf("\nHP: %d Mvt: %d> ", player.HP, player.Movement), "primary")
+		player.ShowPrompt()
 	}
 }
 
 func (n *Notifier) NotifyAll(message string) {
-	for _, player := range n.Players {
+	for _, player := range n.Registry.All() {
 		display.PrintWithColor(player, message, "primary")
-		display.PrintWithColor(player, fmt.Sprintf("\nHP: %d Mvt: %d> ", player.HP, player.Movement), "primary")
+		player.ShowPrompt()
 	}
 }
 
 func (n *Notifier) NotifyPlayer(playerUUID string, message string) {
-	player := n.Players[playerUUID]
+	player, ok := n.Registry.Get(playerUUID)
+	if !ok {
+		return
+	}
 	display.PrintWithColor(player, message, "primary")
-	display.PrintWithColor(player, fmt.Sprintf("\nHP: %d Mvt: %d> ", player.HP, player.Movement), "primary")
+	player.ShowPrompt()
 }
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"mud/players"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// runPlayer looks after accounts and characters from outside the game, ie
+// for making the first admin or letting somebody back in who has forgotten
+// their password.
+func runPlayer(args []string) error {
+	flags, configPath := newFlagSet("player")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		return usage("player")
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+	case args[0] == "reset-password" && len(args) == 2:
+	case args[0] == "grant" && (len(args) == 2 || len(args) == 3):
+	default:
+		return usage("player")
+	}
+
+	_, db, err := openConfigured(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "list":
+		return listPlayers(db)
+	case "reset-password":
+		return resetPassword(db, args[1])
+	default:
+		role := "admin"
+		if len(args) == 3 {
+			role = args[2]
+		}
+		err = grantRole(db, args[1], role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Granted %s the %s role\n", args[1], role)
+		return nil
+	}
+}
+
+func listPlayers(db *sqlx.DB) error {
+	var accounts []players.Account
+	err := db.Select(&accounts, "SELECT uuid, username, password, created_at, COALESCE(roles, 'player') AS roles FROM accounts ORDER BY username")
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		names, err := account.GetCharacterNames(db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-20s %-20s %s\n", account.Username, account.Roles, strings.Join(names, ", "))
+	}
+
+	// characters from before accounts, which get one when they next log in
+	var legacy []string
+	err = db.Select(&legacy, "SELECT name FROM players WHERE account_uuid IS NULL OR account_uuid = '' ORDER BY name")
+	if err != nil {
+		return err
+	}
+	for _, name := range legacy {
+		fmt.Printf("%-20s %-20s %s\n", name, "(no account)", name)
+	}
+	return nil
+}
+
+// resetPassword gives the account a new random password and prints it.  The
+// name is an account's username, or one of its characters.
+func resetPassword(db *sqlx.DB, name string) error {
+	password, err := randomPassword(12)
+	if err != nil {
+		return err
+	}
+	hashed := players.HashPassword(password)
+
+	account, err := players.GetAccountFromDB(db, name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if account == nil {
+		var character struct {
+			UUID        string `db:"uuid"`
+			AccountUUID string `db:"account_uuid"`
+		}
+		err = db.Get(&character, "SELECT uuid, COALESCE(account_uuid, '') AS account_uuid FROM players WHERE LOWER(name) = LOWER(?)", name)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("there's no account or character called %s", name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if character.AccountUUID == "" {
+			err = players.SetLegacyPassword(db, character.UUID, hashed)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("New password for %s: %s\n", name, password)
+			return nil
+		}
+		account, err = players.GetAccountByUUID(db, character.AccountUUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = account.SetPassword(db, hashed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("New password for %s: %s\n", account.Username, password)
+	return nil
+}
+
+const passwordChars = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func randomPassword(length int) (string, error) {
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(passwordChars))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordChars[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// grantRole gives an account a role, for making the first admin before there
+// is anybody around to do it from inside the game.
+func grantRole(db *sqlx.DB, username string, roleName string) error {
+	role, err := players.ParseRole(roleName)
+	if err != nil {
+		return err
+	}
+	account, err := players.GetAccountFromDB(db, username)
+	if err != nil {
+		return fmt.Errorf("error finding account %s: %v", username, err)
+	}
+	roles := players.ParseRoles(account.Roles)
+	for _, existing := range roles {
+		if existing == role {
+			return nil
+		}
+	}
+	return players.SetAccountRoles(db, account.UUID, append(roles, role))
+}
@@ -0,0 +1,118 @@
+package players
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MaxCharactersPerAccount is how many characters one account may have.
+var MaxCharactersPerAccount = 5
+
+// An Account is who logs in, and owns any number of characters (up to
+// MaxCharactersPerAccount).  Roles is a comma separated list, ie "player,admin".
+type Account struct {
+	UUID      string    `db:"uuid"`
+	Username  string    `db:"username"`
+	Password  string    `db:"password"`
+	CreatedAt time.Time `db:"created_at"`
+	Roles     string    `db:"roles"`
+}
+
+func GetAccountFromDB(db *sqlx.DB, username string) (*Account, error) {
+	var account Account
+	err := db.Get(&account, "SELECT uuid, username, password, created_at, COALESCE(roles, 'player') AS roles FROM accounts WHERE LOWER(username) = LOWER(?)", username)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func GetAccountByUUID(db *sqlx.DB, accountUUID string) (*Account, error) {
+	var account Account
+	err := db.Get(&account, "SELECT uuid, username, password, created_at, COALESCE(roles, 'player') AS roles FROM accounts WHERE uuid = ?", accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// CreateAccount stores a new account.  password should already be hashed.
+func CreateAccount(db *sqlx.DB, username string, password string) (*Account, error) {
+	_, err := GetAccountFromDB(db, username)
+	if err == nil {
+		return nil, fmt.Errorf("the account name %s is taken", username)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	account := &Account{
+		UUID:      uuid.New().String(),
+		Username:  username,
+		Password:  password,
+		CreatedAt: time.Now(),
+		Roles:     "player",
+	}
+	_, err = db.Exec("INSERT INTO accounts (uuid, username, password, created_at, roles) VALUES (?, ?, ?, ?, ?)",
+		account.UUID, account.Username, account.Password, account.CreatedAt, account.Roles)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GetCharacterNames lists the account's characters, alphabetically.
+func (account *Account) GetCharacterNames(db *sqlx.DB) ([]string, error) {
+	var names []string
+	err := db.Select(&names, "SELECT name FROM players WHERE account_uuid = ? ORDER BY name", account.UUID)
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (account *Account) CanCreateCharacter(db *sqlx.DB) (bool, error) {
+	var count int
+	err := db.Get(&count, "SELECT COUNT(*) FROM players WHERE account_uuid = ?", account.UUID)
+	if err != nil {
+		return false, err
+	}
+	return count < MaxCharactersPerAccount, nil
+}
+
+// migrateLegacyPlayer turns a character from before accounts existed into an
+// account of the same name and password, with that character as its only one.
+func migrateLegacyPlayer(db *sqlx.DB, player *Player) (*Account, error) {
+	account, err := CreateAccount(db, player.Name, player.Password)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec("UPDATE players SET account_uuid = ? WHERE uuid = ?", account.UUID, player.UUID)
+	if err != nil {
+		return nil, err
+	}
+	player.AccountUUID = account.UUID
+	return account, nil
+}
+
+// SetPassword changes the account's password.  password should already be
+// hashed.
+func (account *Account) SetPassword(db *sqlx.DB, password string) error {
+	_, err := db.Exec("UPDATE accounts SET password = ? WHERE uuid = ?", password, account.UUID)
+	if err != nil {
+		return err
+	}
+	account.Password = password
+	return nil
+}
+
+// SetLegacyPassword changes the password of a character from before accounts
+// existed, which becomes its account's password when it next logs in.
+func SetLegacyPassword(db *sqlx.DB, playerUUID string, password string) error {
+	_, err := db.Exec("UPDATE players SET password = ? WHERE uuid = ?", password, playerUUID)
+	return err
+}
@@ -0,0 +1,56 @@
+package players
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MaxAliases is how many aliases one character may have.
+var MaxAliases = 50
+
+// GetAliasesFromDB loads the player's aliases, which are kept by lower case
+// name.
+func (player *Player) GetAliasesFromDB(db *sqlx.DB) error {
+	var aliases []struct {
+		Name      string `db:"name"`
+		Expansion string `db:"expansion"`
+	}
+	err := db.Select(&aliases, "SELECT name, expansion FROM player_aliases WHERE player_uuid = ?", player.UUID)
+	if err != nil {
+		return err
+	}
+
+	player.Aliases = make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		player.Aliases[strings.ToLower(alias.Name)] = alias.Expansion
+	}
+	return nil
+}
+
+// SetAlias adds the alias, or changes what it expands to.
+func (player *Player) SetAlias(db *sqlx.DB, name string, expansion string) error {
+	name = strings.ToLower(name)
+	_, err := db.Exec("INSERT INTO player_aliases (player_uuid, name, expansion) VALUES (?, ?, ?) ON CONFLICT (player_uuid, name) DO UPDATE SET expansion = excluded.expansion",
+		player.UUID, name, expansion)
+	if err != nil {
+		return err
+	}
+
+	if player.Aliases == nil {
+		player.Aliases = make(map[string]string)
+	}
+	player.Aliases[name] = expansion
+	return nil
+}
+
+// RemoveAlias deletes the alias.
+func (player *Player) RemoveAlias(db *sqlx.DB, name string) error {
+	name = strings.ToLower(name)
+	_, err := db.Exec("DELETE FROM player_aliases WHERE player_uuid = ? AND name = ?", player.UUID, name)
+	if err != nil {
+		return err
+	}
+	delete(player.Aliases, name)
+	return nil
+}
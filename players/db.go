@@ -21,11 +21,12 @@ func setPlayerLoggedInStatusInDB(db *sqlx.DB, playerUUID string, loggedIn bool)
 func GetPlayerByName(db *sqlx.DB, name string) (*Player, error) {
 	var player Player
 	var playerAbilities PlayerAbilities
-	err := db.QueryRow("SELECT p.uuid, p.name, p.room, p.area, p.hp, p.movement, p.logged_in, pa.intelligence, pa.dexterity, pa.charisma, pa.constitution, pa.wisdom, pa.strength FROM players p JOIN player_attributes pa ON p.uuid = pa.player_uuid WHERE LOWER(p.name) = LOWER(?)", name).
+	err := db.QueryRow("SELECT p.uuid, p.name, p.room, p.area, p.hp, p.movement, p.logged_in, pa.intelligence, pa.dexterity, pa.charisma, pa.constitution, pa.wisdom, pa.strength FROM players p JOIN player_abilities pa ON p.uuid = pa.player_uuid WHERE LOWER(p.name) = LOWER(?)", name).
 		Scan(&player.UUID, &player.Name, &player.RoomUUID, &player.AreaUUID, &player.HP, &player.Movement, &player.LoggedIn, &playerAbilities.Intelligence, &playerAbilities.Dexterity, &playerAbilities.Charisma, &playerAbilities.Constitution, &playerAbilities.Wisdom, &playerAbilities.Strength)
 	if err != nil {
 		return nil, err
 	}
+	player.PlayerAbilities = playerAbilities
 	return &player, nil
 }
 
@@ -34,8 +35,8 @@ func GetPlayerFromDB(db *sqlx.DB, playerName string) (*Player, error) {
 	var colorProfileUUID string
 	var characterClassArchetypeSlug string
 	var characterRaceSlug, characterSubRaceSlug string
-	err := db.QueryRow("SELECT uuid, name, character_class, race, subrace, room, area, hp, hp_max, movement, movement_max, logged_in, password, color_profile FROM players WHERE LOWER(name) = LOWER(?)", playerName).
-		Scan(&player.UUID, &player.Name, &characterClassArchetypeSlug, &characterRaceSlug, &characterSubRaceSlug, &player.RoomUUID, &player.AreaUUID, &player.HP, &player.HPMax, &player.Movement, &player.MovementMax, &player.LoggedIn, &player.Password, &colorProfileUUID)
+	err := db.QueryRow("SELECT uuid, COALESCE(account_uuid, ''), name, character_class, race, subrace, room, area, hp, hp_max, movement, movement_max, logged_in, COALESCE(password, ''), color_profile, COALESCE(prompt, '') FROM players WHERE LOWER(name) = LOWER(?)", playerName).
+		Scan(&player.UUID, &player.AccountUUID, &player.Name, &characterClassArchetypeSlug, &characterRaceSlug, &characterSubRaceSlug, &player.RoomUUID, &player.AreaUUID, &player.HP, &player.HPMax, &player.Movement, &player.MovementMax, &player.LoggedIn, &player.Password, &colorProfileUUID, &player.Prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +77,19 @@ func (player *Player) GetColorProfileFromDB(db *sqlx.DB) error {
 	return nil
 }
 
+func (player *Player) GetPlayerAbilitiesFromDB(db *sqlx.DB) error {
+	var playerAbilities PlayerAbilities
+	query := `SELECT uuid, player_uuid, strength, intelligence, wisdom, constitution, charisma, dexterity
+			  FROM player_abilities
+			  WHERE player_uuid = ?`
+	err := db.QueryRow(query, player.UUID).Scan(&playerAbilities.UUID, &playerAbilities.PlayerUUID, &playerAbilities.Strength, &playerAbilities.Intelligence, &playerAbilities.Wisdom, &playerAbilities.Constitution, &playerAbilities.Charisma, &playerAbilities.Dexterity)
+	if err != nil {
+		return err
+	}
+	player.PlayerAbilities = playerAbilities
+	return nil
+}
+
 func (player *Player) GetInventoryFromDB(db *sqlx.DB) error {
 	queryString := `SELECT i.uuid, i.name, i.description, i.equipment_slots FROM item_locations il JOIN items i ON il.player_uuid = ? AND il.item_uuid = i.uuid;`
 	rows, err := db.Query(queryString, player.UUID)
@@ -0,0 +1,56 @@
+package players
+
+import (
+	"fmt"
+	"mud/items"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// New characters start out in the start room, and dead ones wake up in the
+// respawn room, which is the start room unless the server says otherwise.
+var (
+	StartAreaUUID     = "d71e8cf1-d5ba-426c-8915-4c7f5b22e3a9"
+	StartRoomUUID     = "189a729d-4e40-4184-a732-e2c45c66ff46"
+	RespawnRoomUUID   = StartRoomUUID
+	RespawnHPFraction = 0.5
+)
+
+// Rooms keeps track of which players are in which room, ie the world state.
+type Rooms interface {
+	RemovePlayerFromRoom(roomUUID string, player *Player) error
+	AddPlayerToRoom(roomUUID string, player *Player) error
+}
+
+// Die leaves the player's corpse, and everything they were carrying, in the
+// room they died in, then sends them back to the respawn room with some of
+// their hit points restored.
+func (player *Player) Die(db *sqlx.DB, rooms Rooms) (*items.Item, error) {
+	corpse, err := items.NewCorpse(db, player.Name, player.RoomUUID, player.Inventory)
+	if err != nil {
+		return nil, fmt.Errorf("error creating corpse for %s: %v", player.Name, err)
+	}
+	player.Inventory = []*items.Item{}
+	player.SendItems()
+
+	if rooms != nil {
+		if err := rooms.RemovePlayerFromRoom(player.RoomUUID, player); err != nil {
+			fmt.Printf("error removing %s from room %s: %v\n", player.Name, player.RoomUUID, err)
+		}
+		if err := rooms.AddPlayerToRoom(RespawnRoomUUID, player); err != nil {
+			return corpse, fmt.Errorf("error respawning %s: %v", player.Name, err)
+		}
+	}
+	if err := player.SetLocation(db, RespawnRoomUUID); err != nil {
+		return corpse, fmt.Errorf("error respawning %s: %v", player.Name, err)
+	}
+
+	hp := int32(float64(player.HPMax) * RespawnHPFraction)
+	if hp < 1 {
+		hp = 1
+	}
+	if err := player.SetHP(db, hp); err != nil {
+		return corpse, fmt.Errorf("error restoring hp for %s: %v", player.Name, err)
+	}
+	return corpse, nil
+}
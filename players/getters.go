@@ -1,9 +1,35 @@
 package players
 
 import (
-	"mud/items"
+	"mud/combat"
 )
 
+func (player *Player) GetName() string {
+	return player.Name
+}
+
+func (player *Player) GetHP() int32 {
+	return player.HP
+}
+
+func (player *Player) GetAbilities() combat.Abilities {
+	return player.PlayerAbilities
+}
+
+// TODO races and equipment should be able to grant immunities, resistances
+// and vulnerabilities.
+func (player *Player) IsImmuneTo(damage combat.DamageRoll) bool {
+	return false
+}
+
+func (player *Player) IsResistantTo(damage combat.DamageRoll) bool {
+	return false
+}
+
+func (player *Player) IsVulnerableTo(damage combat.DamageRoll) bool {
+	return false
+}
+
 func (player *Player) GetArmorClass() int32 {
 	// 10 + armor_bonus + shield_bonus + dexterity_modifier + other_modifiers
 	base := int32(10)
@@ -25,16 +51,6 @@ func (player *Player) GetRace() string {
 	return player.Race.Name + " - " + player.Race.SubRaceName
 }
 
-func (player Player) GetItemFromInventory(itemName string) *items.Item {
-	inventory := player.Inventory
-	for idx := range inventory {
-		if inventory[idx].GetName() == itemName {
-			return inventory[idx]
-		}
-	}
-	return nil
-}
-
 func (player *Player) GetSizeModifier() int32 {
 	// Need to update this.  Probably need to move this out, so it can be used by players and monsters
 
@@ -0,0 +1,52 @@
+package players
+
+import (
+	"log"
+	"mud/transport"
+)
+
+type gmcpVitals struct {
+	HP          int32 `json:"hp"`
+	HPMax       int32 `json:"maxhp"`
+	Movement    int32 `json:"mv"`
+	MovementMax int32 `json:"maxmv"`
+}
+
+type gmcpItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type gmcpItemList struct {
+	Location string     `json:"location"`
+	Items    []gmcpItem `json:"items"`
+}
+
+// SendGMCP sends out of band data to clients which understand it, and quietly
+// does nothing for everyone else.
+func (player *Player) SendGMCP(pkg string, data interface{}) {
+	sender, ok := player.Session.(transport.GMCPSender)
+	if !ok {
+		return
+	}
+	if err := sender.SendGMCP(pkg, data); err != nil {
+		log.Printf("error sending %s to %s: %v", pkg, player.Name, err)
+	}
+}
+
+func (player *Player) SendVitals() {
+	player.SendGMCP("Char.Vitals", gmcpVitals{
+		HP:          player.HP,
+		HPMax:       player.HPMax,
+		Movement:    player.Movement,
+		MovementMax: player.MovementMax,
+	})
+}
+
+func (player *Player) SendItems() {
+	itemList := gmcpItemList{Location: "inv", Items: []gmcpItem{}}
+	for _, item := range player.Inventory {
+		itemList.Items = append(itemList.Items, gmcpItem{ID: item.UUID, Name: item.Name})
+	}
+	player.SendGMCP("Char.Items.List", itemList)
+}
@@ -0,0 +1,118 @@
+package players
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+type PlayerKey struct {
+	ID          int64     `db:"id"`
+	PlayerUUID  string    `db:"player_uuid"`
+	PublicKey   string    `db:"public_key"`
+	Fingerprint string    `db:"fingerprint"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// parseKey takes a key in authorized_keys format, ie "ssh-ed25519 AAAA... me@laptop",
+// and gives it back without the comment along with its fingerprint.
+func parseKey(authorizedKey string) (string, string, error) {
+	key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return "", "", fmt.Errorf("that doesn't look like a public key: %v", err)
+	}
+	return strings.TrimSpace(string(gossh.MarshalAuthorizedKey(key))), gossh.FingerprintSHA256(key), nil
+}
+
+func AddPlayerKey(db *sqlx.DB, playerUUID string, authorizedKey string) (*PlayerKey, error) {
+	publicKey, fingerprint, err := parseKey(authorizedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	err = db.Get(&count, "SELECT COUNT(*) FROM player_keys WHERE player_uuid = ? AND fingerprint = ?", playerUUID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("you have already added the key %s", fingerprint)
+	}
+
+	playerKey := &PlayerKey{PlayerUUID: playerUUID, PublicKey: publicKey, Fingerprint: fingerprint, CreatedAt: time.Now()}
+	result, err := db.Exec("INSERT INTO player_keys (player_uuid, public_key, fingerprint, created_at) VALUES (?, ?, ?, ?)",
+		playerKey.PlayerUUID, playerKey.PublicKey, playerKey.Fingerprint, playerKey.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	playerKey.ID, err = result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return playerKey, nil
+}
+
+func GetPlayerKeys(db *sqlx.DB, playerUUID string) ([]PlayerKey, error) {
+	var keys []PlayerKey
+	err := db.Select(&keys, "SELECT id, player_uuid, public_key, fingerprint, created_at FROM player_keys WHERE player_uuid = ? ORDER BY id", playerUUID)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func RemovePlayerKey(db *sqlx.DB, playerUUID string, fingerprint string) error {
+	result, err := db.Exec("DELETE FROM player_keys WHERE player_uuid = ? AND fingerprint = ?", playerUUID, fingerprint)
+	if err != nil {
+		return err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("no key with fingerprint %s", fingerprint)
+	}
+	return nil
+}
+
+// HasPlayerKey checks whether anyone has added the key with the fingerprint.
+func HasPlayerKey(db *sqlx.DB, fingerprint string) (bool, error) {
+	var count int
+	err := db.Get(&count, "SELECT COUNT(*) FROM player_keys WHERE fingerprint = ?", fingerprint)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetPublicKey finds the key with the fingerprint, in authorized_keys format.
+func GetPublicKey(db *sqlx.DB, fingerprint string) (string, error) {
+	var publicKey string
+	err := db.Get(&publicKey, "SELECT public_key FROM player_keys WHERE fingerprint = ? LIMIT 1", fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("no key with fingerprint %s", fingerprint)
+	}
+	return publicKey, nil
+}
+
+// GetPlayerNamesForKey finds every character the key with the fingerprint has
+// been added to.
+func GetPlayerNamesForKey(db *sqlx.DB, fingerprint string) ([]string, error) {
+	var names []string
+	query := `
+		SELECT p.name
+		FROM player_keys pk
+		JOIN players p ON pk.player_uuid = p.uuid
+		WHERE pk.fingerprint = ?
+		ORDER BY p.name
+	`
+	err := db.Select(&names, query, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -7,40 +7,46 @@ import (
 	"log"
 	"mud/character_classes"
 	"mud/display"
+	"mud/transport"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/ssh"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// What new characters start out with.
+var (
+	DefaultColorProfileUUID       = "2c7dfd5b-d160-42e0-accb-b77d9686dbea"
+	StartingMovement        int32 = 100
+)
+
 // func getPlayerInput(reader io.Reader) string {
 // 	r := bufio.NewReader(reader)
 // 	input, _ := r.ReadString('\n')
 // 	return strings.TrimSpace(input)
 // }
 
-func getPlayerInput(session ssh.Session) string {
-	var input strings.Builder
-	for {
-		b := make([]byte, 1)
-		_, err := session.Read(b)
-		if err != nil {
-			log.Printf("Error reading input: %v", err)
-			return ""
-		}
-		if b[0] == '\r' || b[0] == '\n' {
-			fmt.Fprintln(session) // Print newline
-			break
-		}
-		input.Write(b)
-		fmt.Fprint(session, string(b)) // Echo the character
+func getPlayerInput(session transport.Conn) string {
+	input, err := session.ReadLine()
+	if err != nil {
+		log.Printf("Error reading input: %v", err)
+		return ""
 	}
-	return strings.TrimSpace(input.String())
+	return strings.TrimSpace(input)
+}
+
+// getPlayerPassword reads input without echoing it back.
+func getPlayerPassword(session transport.Conn) string {
+	session.SetEcho(false)
+	defer session.SetEcho(true)
+
+	return getPlayerInput(session)
 }
 func HashPassword(password string) string {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -77,7 +83,7 @@ func getCharacterRaceNamesFromCharacterRaceObjects(c character_classes.Character
 	return characterRaceNames
 }
 
-func selectCharacterClassAndArchetype(session ssh.Session, db *sqlx.DB, player *Player) *character_classes.CharacterClass {
+func selectCharacterClassAndArchetype(session transport.Conn, db *sqlx.DB, player *Player) *character_classes.CharacterClass {
 	characterClasses, err := character_classes.GetCharacterClassList(db, "")
 	if err != nil {
 		log.Fatal(err)
@@ -143,7 +149,7 @@ func selectCharacterClassAndArchetype(session ssh.Session, db *sqlx.DB, player *
 	}
 }
 
-func selectRace(session ssh.Session, db *sqlx.DB, player *Player) *character_classes.CharacterRace {
+func selectRace(session transport.Conn, db *sqlx.DB, player *Player) *character_classes.CharacterRace {
 	characterRaces, err := character_classes.GetCharacterRaceList(db, "", "")
 	if err != nil {
 		log.Fatal(err)
@@ -226,21 +232,19 @@ func selectRace(session ssh.Session, db *sqlx.DB, player *Player) *character_cla
 
 }
 
-func createPlayer(session ssh.Session, db *sqlx.DB, playerName string) (*Player, error) {
+func createPlayer(session transport.Conn, db *sqlx.DB, account *Account, playerName string) (*Player, error) {
 	player := NewPlayer(session)
 	player.Name = playerName
-
-	fmt.Fprintf(session, "Please enter a password you'd like to use: ")
-	password := getPlayerInput(session)
-	player.Password = HashPassword(password)
+	player.AccountUUID = account.UUID
+	player.Roles = ParseRoles(account.Roles)
 
 	// default start point
-	player.AreaUUID = "d71e8cf1-d5ba-426c-8915-4c7f5b22e3a9"
-	player.RoomUUID = "189a729d-4e40-4184-a732-e2c45c66ff46"
+	player.AreaUUID = StartAreaUUID
+	player.RoomUUID = StartRoomUUID
 	player.UUID = uuid.New().String()
 
 	// "default" light mode color profile.  Should let the user choose?
-	colorProfile, err := getColorProfileFromDB(db, "2c7dfd5b-d160-42e0-accb-b77d9686dbea")
+	colorProfile, err := getColorProfileFromDB(db, DefaultColorProfileUUID)
 	if err != nil {
 		return nil, err
 	}
@@ -260,8 +264,8 @@ func createPlayer(session ssh.Session, db *sqlx.DB, playerName string) (*Player,
 	player.ColorProfile = *colorProfile
 	player.HP = int32(chosenCharacterClass.HPAtFirstLevel)
 	player.HPMax = int32(chosenCharacterClass.HPAtFirstLevel)
-	player.Movement = 100
-	player.MovementMax = 100
+	player.Movement = StartingMovement
+	player.MovementMax = StartingMovement
 	player.UUID = uuid.New().String()
 	player.Session = session
 	player.CharacterClass = *chosenCharacterClass
@@ -272,8 +276,8 @@ func createPlayer(session ssh.Session, db *sqlx.DB, playerName string) (*Player,
 		log.Fatal(err)
 	}
 
-	_, err = tx.Exec("INSERT INTO players (uuid, character_class, race, subrace, name, area, room, hp, hp_max, movement, movement_max, color_profile, password, logged_in) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		player.UUID, player.CharacterClass.ArchetypeSlug, player.Race.Slug, player.Race.SubRaceSlug, player.Name, player.AreaUUID, player.RoomUUID, player.HP, player.HPMax, player.Movement, player.MovementMax, player.ColorProfile.GetUUID(), player.Password, true)
+	_, err = tx.Exec("INSERT INTO players (uuid, account_uuid, character_class, race, subrace, name, area, room, hp, hp_max, movement, movement_max, color_profile, logged_in) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		player.UUID, player.AccountUUID, player.CharacterClass.ArchetypeSlug, player.Race.Slug, player.Race.SubRaceSlug, player.Name, player.AreaUUID, player.RoomUUID, player.HP, player.HPMax, player.Movement, player.MovementMax, player.ColorProfile.GetUUID(), true)
 	if err != nil {
 		tx.Rollback()
 		log.Fatalf("Failed to insert player: %v", err)
@@ -295,6 +299,7 @@ func createPlayer(session ssh.Session, db *sqlx.DB, playerName string) (*Player,
 		log.Fatalf("Failed to set player equipments: %v", err)
 	}
 	player.Equipment = *NewPlayerEquipment()
+	player.PlayerAbilities = PlayerAbilities{Strength: 10, Intelligence: 10, Wisdom: 10, Constitution: 10, Charisma: 10, Dexterity: 10}
 
 	err = tx.Commit()
 	if err != nil {
@@ -306,8 +311,9 @@ func createPlayer(session ssh.Session, db *sqlx.DB, playerName string) (*Player,
 	return player, nil
 }
 
-// Handle the login process for a player.  After authentication,
-// cycle through related fields to populate the `player` object:
+// Handle the login process for a player.  After authenticating the account
+// and picking (or creating) one of its characters, cycle through related
+// fields to populate the `player` object:
 // - ColorProfile
 // - Equipment
 // - etc
@@ -315,40 +321,239 @@ func createPlayer(session ssh.Session, db *sqlx.DB, playerName string) (*Player,
 // each one of these steps results in another database query, but I thought it
 // best to keep the actions atomic for now, rather than trying to build one
 // huge query which has joins all over the place.
-func LoginPlayer(session ssh.Session, db *sqlx.DB) (*Player, error) {
+func LoginPlayer(session transport.Conn, db *sqlx.DB) (*Player, error) {
+	// players who connected with a key they have added don't need a password
+	if keyed, ok := session.(transport.KeyAuthenticated); ok && keyed.KeyFingerprint() != "" {
+		player, err := loginWithKey(session, db, keyed.KeyFingerprint())
+		if err != nil || player != nil {
+			return player, err
+		}
+	}
+
+	account, err := loginAccount(session, db)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Fprintf(session, "Welcome! Please enter your player name: ")
-	playerName := getPlayerInput(session)
+	return selectAccountCharacter(session, db, account)
+}
 
-	player, err := GetPlayerFromDB(db, playerName)
+// loginAccount asks for an account name and password, offering to create the
+// account when there isn't one by that name.
+func loginAccount(session transport.Conn, db *sqlx.DB) (*Account, error) {
+	fmt.Fprintf(session, "Welcome! Please enter your account name: ")
+	username := getPlayerInput(session)
+	if username == "" {
+		return nil, errors.New("No account name given")
+	}
+
+	account, err := GetAccountFromDB(db, username)
+	if err == sql.ErrNoRows {
+		// characters from before accounts existed log in the way they used
+		// to, and become an account the first time they do.
+		player, err := GetPlayerFromDB(db, username)
+		if err == nil && player != nil && player.AccountUUID == "" {
+			err = checkPassword(session, player.Name, player.Password)
+			if err != nil {
+				return nil, err
+			}
+			return migrateLegacyPlayer(db, player)
+		}
+
+		fmt.Fprintf(session, "Account not found.  Do you want to create a new account? (y/n): ")
+		answer := getPlayerInput(session)
+		if strings.ToLower(answer) != "y" {
+			return nil, errors.New("Account does not exist")
+		}
+
+		fmt.Fprintf(session, "Please enter a password you'd like to use: ")
+		password := getPlayerPassword(session)
+		return CreateAccount(db, username, HashPassword(password))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPassword(session, account.Username, account.Password)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// checkPassword gives the player a few tries at their password, for as long as
+// LoginAttempts lets them.
+func checkPassword(session transport.Conn, name string, hashedPassword string) error {
+	nameKey := "name:" + strings.ToLower(name)
+	keys := []string{nameKey}
+	if addr := session.RemoteAddr(); addr != nil {
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			host = addr.String()
+		}
+		keys = append(keys, "addr:"+host)
+	}
+
+	for attempt := 0; attempt < PasswordAttemptsPerConnection; attempt++ {
+		if wait := LoginAttempts.Wait(keys...); wait > 0 {
+			return fmt.Errorf("Too many failed logins, try again in %s", wait.Round(time.Second))
+		}
+
+		fmt.Fprintf(session, "Please enter your password: ")
+		passwd := getPlayerPassword(session)
+		err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(passwd))
+		if err == nil {
+			// the address keeps its failures, so that guessing at one account
+			// doesn't get forgiven by logging in to another
+			LoginAttempts.Succeeded(nameKey)
+			return nil
+		}
+		LoginAttempts.Failed(keys...)
+		fmt.Fprintf(session, "Wrong password.\n")
+	}
+	return errors.New("Too many wrong passwords")
+}
+
+// selectAccountCharacter lets the player pick which of the account's
+// characters to play, or make a new one if the account has room for it.
+func selectAccountCharacter(session transport.Conn, db *sqlx.DB, account *Account) (*Player, error) {
+	playerNames, err := account.GetCharacterNames(db)
+	if err != nil {
+		return nil, err
+	}
+	if len(playerNames) == 0 {
+		return createAccountCharacter(session, db, account)
+	}
+
+	canCreate, err := account.CanCreateCharacter(db)
+	if err != nil {
+		return nil, err
+	}
+	elements := playerNames
+	if canCreate {
+		elements = append(elements, "Create a new character")
+	}
+
+	choice := selectCharacter(session, elements, "anything else to quit")
+	if choice == -1 {
+		return nil, nil
+	}
+	if choice == len(playerNames) {
+		return createAccountCharacter(session, db, account)
+	}
+
+	player, err := GetPlayerFromDB(db, playerNames[choice])
+	if err != nil {
+		return nil, err
+	}
+	if player == nil {
+		return nil, fmt.Errorf("%s could not be loaded", playerNames[choice])
+	}
+	return loadPlayer(session, db, player)
+}
+
+func createAccountCharacter(session transport.Conn, db *sqlx.DB, account *Account) (*Player, error) {
+	canCreate, err := account.CanCreateCharacter(db)
 	if err != nil {
+		return nil, err
+	}
+	if !canCreate {
+		return nil, fmt.Errorf("An account can only have %d characters", MaxCharactersPerAccount)
+	}
+
+	for {
+		fmt.Fprintf(session, "What would you like to name your new character? ")
+		playerName := getPlayerInput(session)
+		if playerName == "" {
+			return nil, nil
+		}
+
+		_, err := GetPlayerFromDB(db, playerName)
 		if err == sql.ErrNoRows {
-			fmt.Fprintf(session, "Player not found.  Do you want to create a new player? (y/n): ")
-			answer := getPlayerInput(session)
+			return createPlayer(session, db, account, playerName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(session, "There is already a character named %s.\n", playerName)
+	}
+}
 
-			if strings.ToLower(answer) == "y" {
-				player, err = createPlayer(session, db, playerName)
-				if err != nil {
-					return nil, err
-				}
-				return player, nil
-			} else {
-				return nil, errors.New("Player does not exist")
-			}
+// loginWithKey logs in the character the key belongs to, or lets the player
+// pick when the key belongs to several.  A nil player means they would rather
+// log in with a name and password.
+func loginWithKey(session transport.Conn, db *sqlx.DB, fingerprint string) (*Player, error) {
+	playerNames, err := GetPlayerNamesForKey(db, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	var playerName string
+	switch len(playerNames) {
+	case 0:
+		return nil, nil
+	case 1:
+		playerName = playerNames[0]
+	default:
+		choice := selectCharacter(session, playerNames, "anything else to log in with a password")
+		if choice == -1 {
+			return nil, nil
 		}
+		playerName = playerNames[choice]
+	}
+
+	player, err := GetPlayerFromDB(db, playerName)
+	if err != nil {
 		return nil, err
 	}
+	fmt.Fprintf(session, "Welcome back, %s!\n", player.Name)
+	return loadPlayer(session, db, player)
+}
 
-	fmt.Fprintf(session, "Please enter your password: ")
-	passwd := getPlayerInput(session)
-	err = bcrypt.CompareHashAndPassword([]byte(player.Password), []byte(passwd))
+// selectCharacter shows a numbered menu of choices and returns the index of
+// the one picked, or -1 when the answer isn't one of them.
+func selectCharacter(session transport.Conn, choices []string, otherwise string) int {
+	menuTitle := "Choose a Character"
+	delimiter := "number"
+	lineStyle := "double"
+
+	// nobody is logged in yet, so there are no colors to print the menu with
+	loginScreen := NewPlayer(session)
+	display.PrintMenu(loginScreen, display.MenuContents{
+		Title:     &menuTitle,
+		Delimiter: &delimiter,
+		LineStyle: &lineStyle,
+		MaxWidth:  65,
+		Elements:  choices,
+	})
+
+	display.PrintWithColor(loginScreen, fmt.Sprintf("Make a selection (1-%d, %s): ", len(choices), otherwise), "primary")
+	choice, err := strconv.Atoi(getPlayerInput(session))
+	if err != nil || choice < 1 || choice > len(choices) {
+		return -1
+	}
+	return choice - 1
+}
+
+// Reconnect logs a character straight back in without asking for a password,
+// for connections which were kept open across a copyover.
+func Reconnect(session transport.Conn, db *sqlx.DB, playerName string) (*Player, error) {
+	player, err := GetPlayerFromDB(db, playerName)
 	if err != nil {
 		return nil, err
 	}
+	if player == nil {
+		return nil, fmt.Errorf("%s could not be loaded", playerName)
+	}
+	return loadPlayer(session, db, player)
+}
 
+// loadPlayer fills in everything else about an authenticated player, and marks
+// them as logged in.
+func loadPlayer(session transport.Conn, db *sqlx.DB, player *Player) (*Player, error) {
 	player.Session = session
 
-	err = player.GetColorProfileFromDB(db)
+	err := player.GetColorProfileFromDB(db)
 	if err != nil {
 		return nil, err
 	}
@@ -358,11 +563,26 @@ func LoginPlayer(session ssh.Session, db *sqlx.DB) (*Player, error) {
 		return nil, err
 	}
 
+	err = player.GetPlayerAbilitiesFromDB(db)
+	if err != nil {
+		return nil, err
+	}
+
 	err = player.GetInventoryFromDB(db)
 	if err != nil {
 		return nil, err
 	}
 
+	err = player.GetRolesFromDB(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = player.GetAliasesFromDB(db)
+	if err != nil {
+		return nil, err
+	}
+
 	err = setPlayerLoggedInStatusInDB(db, player.UUID, true)
 	if err != nil {
 		return nil, err
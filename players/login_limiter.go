@@ -0,0 +1,147 @@
+package players
+
+import (
+	"sync"
+	"time"
+)
+
+// LoginLimiter slows down password guessing.  Failed attempts are counted per
+// character name and per address, and once either has failed MaxAttempts times
+// it has to wait, twice as long after every further failure.
+type LoginLimiter struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// MaxTracked is how many names and addresses are kept track of, so that
+	// guessing with made up names can't use up all the memory
+	MaxTracked int
+
+	mu        sync.Mutex
+	failures  map[string]*loginFailures
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type loginFailures struct {
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginAttempts is the limiter LoginPlayer checks.
+var LoginAttempts = NewLoginLimiter(5, 30*time.Second, 15*time.Minute)
+
+// PasswordAttemptsPerConnection is how many wrong passwords can be typed before
+// being disconnected.
+var PasswordAttemptsPerConnection = 3
+
+func NewLoginLimiter(maxAttempts int, baseBackoff time.Duration, maxBackoff time.Duration) *LoginLimiter {
+	return &LoginLimiter{
+		MaxAttempts: maxAttempts,
+		BaseBackoff: baseBackoff,
+		MaxBackoff:  maxBackoff,
+		MaxTracked:  10000,
+		failures:    make(map[string]*loginFailures),
+		now:         time.Now,
+	}
+}
+
+// Wait is how much longer any of the keys are locked out for, zero when they
+// can try again.
+func (l *LoginLimiter) Wait(keys ...string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	for _, key := range keys {
+		failures, ok := l.failures[key]
+		if !ok {
+			continue
+		}
+		if l.expired(failures, now) {
+			delete(l.failures, key)
+			continue
+		}
+		if remaining := failures.lockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait
+}
+
+func (l *LoginLimiter) Failed(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	for _, key := range keys {
+		failures, ok := l.failures[key]
+		if !ok {
+			if len(l.failures) >= l.MaxTracked {
+				l.forgetOldest(now)
+			}
+			failures = &loginFailures{}
+			l.failures[key] = failures
+		}
+		failures.count++
+		failures.lastFailure = now
+		if failures.count < l.MaxAttempts {
+			continue
+		}
+
+		doublings := failures.count - l.MaxAttempts
+		if doublings > 20 {
+			doublings = 20
+		}
+		backoff := l.BaseBackoff << doublings
+		if backoff > l.MaxBackoff {
+			backoff = l.MaxBackoff
+		}
+		failures.lockedUntil = now.Add(backoff)
+	}
+}
+
+func (l *LoginLimiter) Succeeded(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.failures, key)
+	}
+}
+
+// expired is whether the failures are old enough to forget about.
+func (l *LoginLimiter) expired(failures *loginFailures, now time.Time) bool {
+	return now.Sub(failures.lastFailure) > l.MaxBackoff && !now.Before(failures.lockedUntil)
+}
+
+// sweep forgets the failures which have expired, every so often, since names
+// which are never tried again are never looked at by Wait.
+func (l *LoginLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, failures := range l.failures {
+		if l.expired(failures, now) {
+			delete(l.failures, key)
+		}
+	}
+}
+
+// forgetOldest makes room by dropping the longest ago failure, preferring
+// ones which aren't locked out.
+func (l *LoginLimiter) forgetOldest(now time.Time) {
+	var oldest string
+	var oldestLocked bool
+	var oldestFailure time.Time
+	for key, failures := range l.failures {
+		locked := now.Before(failures.lockedUntil)
+		if oldest == "" || (oldestLocked && !locked) || (oldestLocked == locked && failures.lastFailure.Before(oldestFailure)) {
+			oldest, oldestLocked, oldestFailure = key, locked, failures.lastFailure
+		}
+	}
+	delete(l.failures, oldest)
+}
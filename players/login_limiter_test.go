@@ -0,0 +1,78 @@
+package players
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewLoginLimiter(3, time.Minute, 10*time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		limiter.Failed("name:bob", "addr:10.0.0.1")
+	}
+	if wait := limiter.Wait("name:bob", "addr:10.0.0.1"); wait != 0 {
+		t.Fatalf("expected no wait before the limit, got %s", wait)
+	}
+
+	limiter.Failed("name:bob", "addr:10.0.0.1")
+	if wait := limiter.Wait("name:bob"); wait != time.Minute {
+		t.Errorf("expected a minute's wait at the limit, got %s", wait)
+	}
+	// the address is locked out too, whichever name it tries
+	if wait := limiter.Wait("name:alice", "addr:10.0.0.1"); wait != time.Minute {
+		t.Errorf("expected the address to wait a minute, got %s", wait)
+	}
+
+	limiter.Failed("name:bob")
+	if wait := limiter.Wait("name:bob"); wait != 2*time.Minute {
+		t.Errorf("expected the wait to double, got %s", wait)
+	}
+	for i := 0; i < 10; i++ {
+		limiter.Failed("name:bob")
+	}
+	if wait := limiter.Wait("name:bob"); wait != 10*time.Minute {
+		t.Errorf("expected the wait to stop at the maximum, got %s", wait)
+	}
+
+	now = now.Add(11 * time.Minute)
+	if wait := limiter.Wait("name:bob"); wait != 0 {
+		t.Errorf("expected the lock out to have expired, got %s", wait)
+	}
+
+	limiter.Failed("name:carol")
+	limiter.Succeeded("name:carol")
+	if _, ok := limiter.failures["name:carol"]; ok {
+		t.Errorf("expected a successful login to clear the failures")
+	}
+}
+
+func TestLoginLimiterForgetsNamesNobodyTriesAgain(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewLoginLimiter(3, time.Minute, 10*time.Minute)
+	limiter.now = func() time.Time { return now }
+	limiter.MaxTracked = 3
+
+	limiter.Failed("name:one")
+	limiter.Failed("name:two")
+	now = now.Add(11 * time.Minute)
+	// the old failures are swept up without ever being waited on
+	limiter.Failed("name:three")
+	if len(limiter.failures) != 1 {
+		t.Errorf("expected the expired failures to be swept up, have %d", len(limiter.failures))
+	}
+
+	for i := 0; i < 3; i++ {
+		limiter.Failed("addr:10.0.0.1")
+	}
+	limiter.Failed("name:four")
+	limiter.Failed("name:five")
+	if len(limiter.failures) != 3 {
+		t.Errorf("expected no more than 3 to be kept track of, have %d", len(limiter.failures))
+	}
+	if wait := limiter.Wait("addr:10.0.0.1"); wait != time.Minute {
+		t.Errorf("expected the locked out address to be kept, got a wait of %s", wait)
+	}
+}
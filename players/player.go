@@ -3,22 +3,23 @@ package players
 import (
 	"fmt"
 	"mud/character_classes"
+	"mud/combat"
 	"mud/display"
 	"mud/items"
+	"mud/transport"
 	"mud/utilities"
 	"reflect"
 
-	"github.com/charmbracelet/ssh"
-
 	"github.com/jmoiron/sqlx"
 )
 
-func NewPlayer(session ssh.Session) *Player {
+func NewPlayer(session transport.Conn) *Player {
 	return &Player{Session: session}
 }
 
 type Player struct {
 	UUID            string
+	AccountUUID     string
 	Name            string
 	RoomUUID        string
 	AreaUUID        string
@@ -26,7 +27,7 @@ type Player struct {
 	HPMax           int32
 	Movement        int32
 	MovementMax     int32
-	Session         ssh.Session
+	Session         transport.Conn
 	Commands        []string
 	ColorProfile    ColorProfile
 	LoggedIn        bool
@@ -36,6 +37,11 @@ type Player struct {
 	Inventory       []*items.Item
 	CharacterClass  character_classes.CharacterClass
 	Race            character_classes.CharacterRace
+	Roles           []Role
+	// what the player's aliases expand to, by lower case name
+	Aliases map[string]string
+	// the player's prompt template, empty for DefaultPrompt
+	Prompt string
 }
 
 func (player *Player) GetColorProfileColor(colorUse string) string {
@@ -48,6 +54,7 @@ func (player *Player) AddItem(db *sqlx.DB, item *items.Item) error {
 		return err
 	}
 	player.Inventory = append(player.Inventory, item)
+	player.SendItems()
 	return nil
 }
 
@@ -63,6 +70,7 @@ func (player *Player) RemoveItem(item *items.Item) error {
 		return fmt.Errorf("item %s is not found in player %s inventory", item.GetUUID(), player.UUID)
 	}
 	player.Inventory = append(player.Inventory[:itemIndex], player.Inventory[itemIndex+1:]...)
+	player.SendItems()
 	return nil
 }
 
@@ -84,23 +92,29 @@ func (player *Player) Regen(db *sqlx.DB) error {
 	if err != nil {
 		return err
 	}
+	player.SendVitals()
 	return nil
 }
 
-func (player *Player) Remove(db *sqlx.DB, itemName string) {
-	if player.Equipment.DominantHand.GetName() == itemName {
-		equippedItem := player.Equipment.DominantHand
-		player.AddItem(db, equippedItem.Item)
+// Remove takes the item off and puts it back in the player's inventory.
+func (player *Player) Remove(db *sqlx.DB, equipped *EquippedItem) error {
+	val := reflect.ValueOf(&player.Equipment).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Field(i)
+		if field.Type() != reflect.TypeOf(equipped) || field.IsNil() || field.Interface() != equipped {
+			continue
+		}
 
-		player.Equipment.DominantHand = nil
-		queryString := fmt.Sprintf("UPDATE player_equipments SET DominantHand = '' WHERE player_uuid = '%s'", player.UUID)
-		_, err := db.Exec(queryString)
+		// the slot comes from the struct, not from anything typed
+		queryString := fmt.Sprintf("UPDATE player_equipments SET %s = '' WHERE player_uuid = ?", val.Type().Field(i).Name)
+		_, err := db.Exec(queryString, player.UUID)
 		if err != nil {
-			fmt.Printf("error setting player_equipment to nil: %v", err)
+			return fmt.Errorf("error setting player_equipment to nil: %v", err)
 		}
-
-		display.PrintWithColor(player, fmt.Sprintf("You remove %s.\n", equippedItem.GetName()), "reset")
+		field.Set(reflect.Zero(field.Type()))
+		return player.AddItem(db, equipped.Item)
 	}
+	return fmt.Errorf("%s isn't equipped", equipped.GetName())
 }
 
 func (player *Player) Equip(db *sqlx.DB, item *items.Item) bool {
@@ -170,17 +184,12 @@ func (player *Player) Equip(db *sqlx.DB, item *items.Item) bool {
 				fmt.Printf("error inserting into player_equipments: %v", err)
 				return false
 			}
-			if columns[idx] == "DominantHand" {
-				equippedItem := EquippedItem{
-					Item:         item,
-					EquippedSlot: columns[idx],
-				}
-				player.Equipment.DominantHand = &equippedItem
-			}
+			reflect.ValueOf(&player.Equipment).Elem().FieldByName(columns[idx]).Set(reflect.ValueOf(NewEquippedItem(item, columns[idx])))
 			return true
 		}
 	}
-	return true
+	// every slot the item fits is taken
+	return false
 }
 
 func printEquipmentElement(player *Player, partName string, getterFunc func() *EquippedItem) {
@@ -215,11 +224,37 @@ func (player *Player) RollInitiative() int32 {
 	return utilities.DiceRoll("1d20")
 }
 
+// Attack rolls to hit the opponent and, if it lands, rolls the damage.  Until
+// weapons carry their own damage dice, anything wielded hits harder than a
+// bare fist.
+func (player *Player) Attack(opponent combat.Opponent) *combat.AttackResult {
+	result := &combat.AttackResult{AttackerName: player.Name, AttackName: "punch"}
+	damageDice := "1d4"
+	damageType := "bludgeoning"
+	if player.Equipment.DominantHand != nil {
+		result.AttackName = player.Equipment.DominantHand.GetName()
+		damageDice = "1d8"
+		damageType = "slashing"
+	}
+
+	result.Hit, result.Critical = combat.AttackRoll(player, opponent)
+	if !result.Hit {
+		return result
+	}
+
+	damage := combat.RollDamageDice(damageDice, result.Critical) + player.PlayerAbilities.GetStrengthModifier()
+	if damage < 1 {
+		damage = 1
+	}
+	result.Damage = append(result.Damage, combat.DamageRoll{Type: damageType, Amount: damage})
+	return result
+}
+
 func (player *Player) GetColorProfilecolor(colorUse string) string {
 	// TODO this ain't right.
 	return player.ColorProfile.Primary
 }
 
-func (player *Player) GetSession() ssh.Session {
+func (player *Player) GetSession() transport.Conn {
 	return player.Session
 }
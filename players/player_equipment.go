@@ -80,6 +80,17 @@ func (pe PlayerEquipment) GetFeet() *EquippedItem {
 	return pe.Feet
 }
 
+// Items lists what's equipped, head to toe.
+func (pe PlayerEquipment) Items() []*EquippedItem {
+	var equipped []*EquippedItem
+	for _, item := range []*EquippedItem{pe.Head, pe.Neck, pe.Chest, pe.Arms, pe.Hands, pe.DominantHand, pe.OffHand, pe.Legs, pe.Feet} {
+		if item != nil {
+			equipped = append(equipped, item)
+		}
+	}
+	return equipped
+}
+
 // TODO working on this, I can't remember at the moment why i left this in here
 func (pe *PlayerEquipment) GetEquippedLocation(db *sqlx.DB, item EquippedItem) string {
 	return "foo"
@@ -0,0 +1,227 @@
+package players
+
+import (
+	"fmt"
+	"mud/display"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultPrompt is the prompt of players who haven't set their own.
+const DefaultPrompt = "HP: %h Mvt: %v> "
+
+// MaxPromptLength is how long a prompt template can be.
+const MaxPromptLength = 100
+
+// PromptTokens are what can go in a prompt, in the order the prompt command
+// lists them.
+var PromptTokens = []struct {
+	Token       string
+	Description string
+}{
+	{"%h", "your hit points"},
+	{"%H", "your most hit points"},
+	{"%v", "your movement"},
+	{"%V", "your most movement"},
+	{"%r", "the room you're in"},
+	{"%a", "the area you're in"},
+	{"%e", "the exits from the room, ie NSE"},
+	{"%c", "how healthy whoever you're fighting is, ie 75%"},
+	{"%t", "the time of day"},
+	{"%%", "a %"},
+}
+
+// PromptContext is what a prompt can show about where the player is, which
+// the players package can't look up itself.
+type PromptContext struct {
+	RoomName string
+	AreaName string
+	// the directions out of the room, ie north
+	Exits []string
+	// whoever the player is fighting, when they are
+	Fighting    bool
+	TargetHP    int32
+	TargetMaxHP int32
+}
+
+var (
+	promptContext   func(player *Player) PromptContext
+	promptContextMu sync.RWMutex
+	// promptNow is the time the prompt shows the time of day for
+	promptNow = time.Now
+)
+
+// SetPromptContext registers how to find out where a player is for their
+// prompt.  Without it, the tokens about the room are left empty.
+func SetPromptContext(lookup func(player *Player) PromptContext) {
+	promptContextMu.Lock()
+	defer promptContextMu.Unlock()
+
+	promptContext = lookup
+}
+
+func lookupPromptContext(player *Player) PromptContext {
+	promptContextMu.RLock()
+	defer promptContextMu.RUnlock()
+
+	if promptContext == nil {
+		return PromptContext{}
+	}
+	return promptContext(player)
+}
+
+// PromptSession is implemented by sessions which show the prompt somewhere
+// of their own, ie the ssh UI's status bar, rather than after the output.
+type PromptSession interface {
+	SetPrompt(prompt string)
+}
+
+// ShowPrompt sends the player their prompt, which is how every message to a
+// player ends.
+func (player *Player) ShowPrompt() {
+	prompt := player.RenderPrompt()
+	if session, ok := player.Session.(PromptSession); ok {
+		session.SetPrompt(prompt)
+		return
+	}
+	display.PrintWithColor(player, "\n"+prompt, "primary")
+}
+
+// RenderPrompt fills in the player's prompt template.  The room is only looked
+// up when the template needs it.
+func (player *Player) RenderPrompt() string {
+	template := player.Prompt
+	if template == "" {
+		template = DefaultPrompt
+	}
+
+	var context *PromptContext
+	where := func() *PromptContext {
+		if context == nil {
+			found := lookupPromptContext(player)
+			context = &found
+		}
+		return context
+	}
+
+	var prompt strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' || i+1 == len(template) {
+			prompt.WriteByte(template[i])
+			continue
+		}
+		i++
+		switch template[i] {
+		case 'h':
+			prompt.WriteString(strconv.Itoa(int(player.HP)))
+		case 'H':
+			prompt.WriteString(strconv.Itoa(int(player.HPMax)))
+		case 'v':
+			prompt.WriteString(strconv.Itoa(int(player.Movement)))
+		case 'V':
+			prompt.WriteString(strconv.Itoa(int(player.MovementMax)))
+		case 'r':
+			prompt.WriteString(where().RoomName)
+		case 'a':
+			prompt.WriteString(where().AreaName)
+		case 'e':
+			prompt.WriteString(exitLetters(where().Exits))
+		case 'c':
+			prompt.WriteString(targetHealth(where()))
+		case 't':
+			prompt.WriteString(timeOfDay(promptNow()))
+		case '%':
+			prompt.WriteByte('%')
+		default:
+			prompt.WriteByte('%')
+			prompt.WriteByte(template[i])
+		}
+	}
+	return prompt.String()
+}
+
+// exitLetters shortens the exits to their first letters, in compass order.
+func exitLetters(exits []string) string {
+	var letters strings.Builder
+	for _, direction := range []string{"north", "east", "south", "west", "up", "down"} {
+		for _, exit := range exits {
+			if strings.EqualFold(exit, direction) {
+				letters.WriteString(strings.ToUpper(direction[:1]))
+				break
+			}
+		}
+	}
+	if letters.Len() == 0 {
+		return "none"
+	}
+	return letters.String()
+}
+
+func targetHealth(context *PromptContext) string {
+	if !context.Fighting || context.TargetMaxHP <= 0 {
+		return ""
+	}
+	percent := context.TargetHP * 100 / context.TargetMaxHP
+	if percent < 0 {
+		percent = 0
+	}
+	return fmt.Sprintf("%d%%", percent)
+}
+
+func timeOfDay(now time.Time) string {
+	switch hour := now.Hour(); {
+	case hour >= 5 && hour < 7:
+		return "dawn"
+	case hour >= 7 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 21:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// ValidatePrompt checks that the template only uses tokens which exist, and
+// won't mess up the player's screen.
+func ValidatePrompt(template string) error {
+	if len(template) > MaxPromptLength {
+		return fmt.Errorf("a prompt can't be longer than %d characters", MaxPromptLength)
+	}
+	if strings.ContainsAny(template, "\r\n\x1b") {
+		return fmt.Errorf("a prompt has to be one line of plain text")
+	}
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			continue
+		}
+		if i+1 == len(template) {
+			return fmt.Errorf("the prompt ends with a %% on its own, use %%%% for a %%")
+		}
+		i++
+		if !strings.ContainsRune("hHvVraect%", rune(template[i])) {
+			return fmt.Errorf("%%%c isn't something a prompt can show", template[i])
+		}
+	}
+	return nil
+}
+
+// SetPrompt saves the player's prompt template, empty goes back to the
+// default.
+func (player *Player) SetPrompt(db *sqlx.DB, template string) error {
+	err := ValidatePrompt(template)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE players SET prompt = ? WHERE uuid = ?", template, player.UUID)
+	if err != nil {
+		return err
+	}
+	player.Prompt = template
+	return nil
+}
@@ -0,0 +1,71 @@
+package players
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	lookups := 0
+	SetPromptContext(func(player *Player) PromptContext {
+		lookups++
+		return PromptContext{
+			RoomName:    "The Square",
+			AreaName:    "Town",
+			Exits:       []string{"west", "North", "up"},
+			Fighting:    true,
+			TargetHP:    15,
+			TargetMaxHP: 20,
+		}
+	})
+	t.Cleanup(func() { SetPromptContext(nil) })
+	promptNow = func() time.Time { return time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { promptNow = time.Now })
+
+	player := &Player{HP: 12, HPMax: 30, Movement: 40, MovementMax: 50}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", "HP: 12 Mvt: 40> "},
+		{"<%h/%Hhp %v/%Vmv %e>", "<12/30hp 40/50mv NWU>"},
+		{"%r in %a, %c, %t", "The Square in Town, 75%, evening"},
+		{"100%% %", "100% %"},
+	}
+	for _, test := range tests {
+		player.Prompt = test.template
+		if got := player.RenderPrompt(); got != test.want {
+			t.Errorf("RenderPrompt(%q) = %q, want %q", test.template, got, test.want)
+		}
+	}
+
+	// the room is looked up once a prompt, and only when it's needed
+	lookups = 0
+	player.Prompt = "%h %r %e %c"
+	player.RenderPrompt()
+	player.Prompt = "%h/%H"
+	player.RenderPrompt()
+	if lookups != 1 {
+		t.Errorf("expected the room to be looked up once, was %d times", lookups)
+	}
+}
+
+func TestValidatePrompt(t *testing.T) {
+	tests := []struct {
+		template string
+		valid    bool
+	}{
+		{"", true},
+		{"<%h/%Hhp %v/%Vmv %r %a %e %c %t 100%%> ", true},
+		{"%x", false},
+		{"50%", false},
+		{"hp\n", false},
+		{"\x1b[31m%h", false},
+		{string(make([]byte, MaxPromptLength+1)), false},
+	}
+	for _, test := range tests {
+		if err := ValidatePrompt(test.template); (err == nil) != test.valid {
+			t.Errorf("ValidatePrompt(%q) = %v, want valid %v", test.template, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package players
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Roles belong to the account, so every one of its characters has them.
+// Everybody is a player; admins can do anything the other roles can.
+type Role string
+
+const (
+	RolePlayer    Role = "player"
+	RoleBuilder   Role = "builder"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var Roles = []Role{RolePlayer, RoleBuilder, RoleModerator, RoleAdmin}
+
+func ParseRole(name string) (Role, error) {
+	for _, role := range Roles {
+		if strings.EqualFold(name, string(role)) {
+			return role, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a role", name)
+}
+
+// ParseRoles turns the comma separated roles column into Roles, skipping
+// anything it doesn't recognise.
+func ParseRoles(roles string) []Role {
+	parsed := []Role{}
+	for _, name := range strings.Split(roles, ",") {
+		role, err := ParseRole(strings.TrimSpace(name))
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, role)
+	}
+	return parsed
+}
+
+func (player *Player) HasRole(role Role) bool {
+	if role == "" || role == RolePlayer {
+		return true
+	}
+	for _, playerRole := range player.Roles {
+		if playerRole == role || playerRole == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRolesFromDB loads the roles of the account the player belongs to.
+// Characters from before accounts existed are only players.
+func (player *Player) GetRolesFromDB(db *sqlx.DB) error {
+	player.Roles = []Role{RolePlayer}
+	if player.AccountUUID == "" {
+		return nil
+	}
+
+	account, err := GetAccountByUUID(db, player.AccountUUID)
+	if err != nil {
+		return err
+	}
+	player.Roles = ParseRoles(account.Roles)
+	return nil
+}
+
+func SetAccountRoles(db *sqlx.DB, accountUUID string, roles []Role) error {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	_, err := db.Exec("UPDATE accounts SET roles = ? WHERE uuid = ?", strings.Join(names, ","), accountUUID)
+	return err
+}
@@ -0,0 +1,50 @@
+package players
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Save writes everything about the player which can change while they play
+// back to the database: where they are, their hit points and movement, what
+// they're carrying and what they have equipped.  Most of it is saved as it
+// changes, this makes sure nothing is lost when the server goes down.
+func (player *Player) Save(db *sqlx.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE players SET area = ?, room = ?, hp = ?, hp_max = ?, movement = ?, movement_max = ? WHERE uuid = ?",
+		player.AreaUUID, player.RoomUUID, player.HP, player.HPMax, player.Movement, player.MovementMax, player.UUID)
+	if err != nil {
+		return fmt.Errorf("error saving %s: %v", player.Name, err)
+	}
+
+	for _, item := range player.Inventory {
+		_, err = tx.Exec("UPDATE item_locations SET room_uuid = '', player_uuid = ?, container_uuid = '' WHERE item_uuid = ?", player.UUID, item.UUID)
+		if err != nil {
+			return fmt.Errorf("error saving %s's inventory: %v", player.Name, err)
+		}
+	}
+
+	equipment := player.Equipment
+	_, err = tx.Exec("UPDATE player_equipments SET Head = ?, Neck = ?, Chest = ?, Arms = ?, Hands = ?, DominantHand = ?, OffHand = ?, Legs = ?, Feet = ? WHERE player_uuid = ?",
+		equippedUUID(equipment.Head), equippedUUID(equipment.Neck), equippedUUID(equipment.Chest), equippedUUID(equipment.Arms),
+		equippedUUID(equipment.Hands), equippedUUID(equipment.DominantHand), equippedUUID(equipment.OffHand), equippedUUID(equipment.Legs),
+		equippedUUID(equipment.Feet), player.UUID)
+	if err != nil {
+		return fmt.Errorf("error saving %s's equipment: %v", player.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+func equippedUUID(equippedItem *EquippedItem) string {
+	if equippedItem == nil || equippedItem.Item == nil {
+		return ""
+	}
+	return equippedItem.UUID
+}
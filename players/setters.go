@@ -2,10 +2,34 @@ package players
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var (
+	moveListeners   []func(playerUUID string, areaUUID string, roomUUID string)
+	moveListenersMu sync.RWMutex
+)
+
+// OnMove registers a function to be told whenever a player changes rooms, so
+// that anything keeping track of who is where can keep up.
+func OnMove(listener func(playerUUID string, areaUUID string, roomUUID string)) {
+	moveListenersMu.Lock()
+	defer moveListenersMu.Unlock()
+
+	moveListeners = append(moveListeners, listener)
+}
+
+func notifyMoveListeners(playerUUID string, areaUUID string, roomUUID string) {
+	moveListenersMu.RLock()
+	defer moveListenersMu.RUnlock()
+
+	for _, listener := range moveListeners {
+		listener(playerUUID, areaUUID, roomUUID)
+	}
+}
+
 func (player *Player) SetLocation(db *sqlx.DB, roomUUID string) error {
 	area_rows, err := db.Query("SELECT area_uuid FROM rooms WHERE uuid=?", roomUUID)
 	if err != nil {
@@ -25,6 +49,7 @@ func (player *Player) SetLocation(db *sqlx.DB, roomUUID string) error {
 
 	player.AreaUUID = areaUUID
 	player.RoomUUID = roomUUID
+	notifyMoveListeners(player.UUID, areaUUID, roomUUID)
 
 	area_rows.Close()
 
@@ -40,7 +65,44 @@ func (player *Player) SetLocation(db *sqlx.DB, roomUUID string) error {
 	if err != nil {
 		return err
 	}
+	player.SendVitals()
 
 	stmt.Close()
 	return nil
 }
+
+// TakeDamage only changes the player's hit points in memory, use SetHP to save
+// them.
+func (player *Player) TakeDamage(amount int32) {
+	player.HP -= amount
+	if player.HP < 0 {
+		player.HP = 0
+	}
+}
+
+func (player *Player) SetHP(db *sqlx.DB, hp int32) error {
+	if hp < 0 {
+		hp = 0
+	}
+	player.HP = hp
+
+	_, err := db.Exec("UPDATE players SET hp = ? WHERE uuid = ?", player.HP, player.UUID)
+	if err != nil {
+		return err
+	}
+	player.SendVitals()
+	return nil
+}
+
+// Restore brings the player back up to full hit points and movement.
+func (player *Player) Restore(db *sqlx.DB) error {
+	player.HP = player.HPMax
+	player.Movement = player.MovementMax
+
+	_, err := db.Exec("UPDATE players SET hp = ?, movement = ? WHERE uuid = ?", player.HP, player.Movement, player.UUID)
+	if err != nil {
+		return err
+	}
+	player.SendVitals()
+	return nil
+}
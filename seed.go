@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"mud/seed"
+)
+
+// runSeed fills the database with the named kinds of data, or all of them,
+// bringing the schema up to date first.
+func runSeed(args []string) error {
+	flags, configPath := newFlagSet("seed")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	names := flags.Args()
+	if len(names) == 0 {
+		names = seed.Order
+	}
+	for _, name := range names {
+		if _, ok := seed.Seeders[name]; !ok {
+			return usage("seed")
+		}
+	}
+
+	cfg, db, err := openConfigured(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = migrateUp(db)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err := seed.Seeders[name](db, cfg)
+		if err != nil {
+			return fmt.Errorf("error seeding %s: %v", name, err)
+		}
+		fmt.Printf("Seeded %s\n", name)
+	}
+	return nil
+}
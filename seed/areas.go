@@ -0,0 +1,264 @@
+package seed
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"mud/config"
+	"mud/mobs"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v2"
+)
+
+// AreaFiles are the areas which make up the world.
+var AreaFiles = []string{"areas/seeds/arena.yml", "areas/seeds/street.yml", "areas/seeds/glade.yml"}
+
+type RoomImport struct {
+	UUID        string            `yaml:"uuid"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Exits       map[string]string `yaml:"exits,omitempty"`
+	Mobs        []string          `yaml:"mobs,omitempty"`
+	Items       []string          `yaml:"items,omitempty"`
+}
+
+type ResetImport struct {
+	Type     string `yaml:"type"`
+	Slug     string `yaml:"slug,omitempty"`
+	Template string `yaml:"template,omitempty"`
+	Room     string `yaml:"room"`
+	Max      int    `yaml:"max,omitempty"`
+}
+
+type AreaImport struct {
+	UUID          string        `yaml:"uuid"`
+	Name          string        `yaml:"name"`
+	Description   string        `yaml:"description"`
+	ResetInterval int           `yaml:"reset_interval,omitempty"`
+	Resets        []ResetImport `yaml:"resets,omitempty"`
+	// behaviors for every mob of a given slug in the area, ie aboleth: [aggressive]
+	MobBehaviors map[string][]string `yaml:"mob_behaviors,omitempty"`
+	Rooms        []RoomImport        `yaml:"rooms"`
+}
+
+// LoadArea reads an area file.
+func LoadArea(path string) (*AreaImport, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var area AreaImport
+	err = yaml.Unmarshal(file, &area)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	return &area, nil
+}
+
+// fetchMobImport looks the mob up by slug in the monster_imports database.
+func fetchMobImport(monstersDB *sqlx.DB, slug string) (mobs.MobDB, error) {
+	var mob mobs.MobDB
+	row := monstersDB.QueryRowx("SELECT * from mob_imports where slug = ?", slug)
+
+	// have to create a result interface, in order to ignore columns in the
+	// table which are not present on the struct
+	result := make(map[string]interface{})
+	err := row.MapScan(result)
+	if err != nil {
+		return mob, fmt.Errorf("error fetching mob %s from mob_imports: %v", slug, err)
+	}
+
+	actions, ok := result["actions"].([]uint8)
+	if !ok {
+		return mob, fmt.Errorf("actions for mob %s is not a []uint8", slug)
+	}
+	delete(result, "actions")
+
+	err = mapstructure.Decode(result, &mob)
+	if err != nil {
+		return mob, fmt.Errorf("failed to fetch mob %s from mob_imports: %v", slug, err)
+	}
+
+	mob.Actions = string(actions)
+	return mob, nil
+}
+
+// insertMob writes the mob into the table, skipping the columns which belong
+// to the table itself.
+func insertMob(db *sqlx.DB, table string, mob mobs.MobDB, skip ...string) error {
+	mapper := reflectx.NewMapperFunc("db", strings.ToLower)
+
+	fieldInfos := mapper.TypeMap(reflect.TypeOf(mobs.Mob{})).Names
+	fields := make([]string, 0, len(fieldInfos))
+	for field := range fieldInfos {
+		// ids are assigned by the table, not copied from the imports
+		if field == "id" || slices.Contains(skip, field) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	columns := strings.Join(fields, ", :")
+	columns = ":" + columns
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(fields, ", "), columns)
+
+	_, err := db.NamedExec(query, mob)
+	return err
+}
+
+// mobImports is where mobs come from when they aren't in mob_templates yet,
+// or why it couldn't be opened.
+type mobImports struct {
+	db  *sqlx.DB
+	err error
+}
+
+func openMobImports(cfg *config.Config) *mobImports {
+	db, err := openImports(cfg.Database.MonsterImportsPath)
+	return &mobImports{db: db, err: err}
+}
+
+func (imports *mobImports) Close() {
+	if imports.db != nil {
+		imports.db.Close()
+	}
+}
+
+// seedMobTemplate copies the mob into mob_templates, so that area resets can
+// make more of them without needing the monster_imports database.
+func seedMobTemplate(db *sqlx.DB, imports *mobImports, slug string) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM mob_templates WHERE slug = ?", slug).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to query mob template count: %v", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if imports.err != nil {
+		return fmt.Errorf("mob %s isn't in mob_templates: %v", slug, imports.err)
+	}
+
+	mob, err := fetchMobImport(imports.db, slug)
+	if err != nil {
+		return err
+	}
+	err = insertMob(db, "mob_templates", mob, "area_uuid", "room_uuid")
+	if err != nil {
+		return fmt.Errorf("failed to insert mob %s into mob_templates: %v", slug, err)
+	}
+	return nil
+}
+
+// Areas adds every area in AreaFiles which isn't in the database yet, with its
+// rooms, mobs and resets.
+func Areas(db *sqlx.DB, cfg *config.Config) error {
+	imports := openMobImports(cfg)
+	if imports.err != nil {
+		return imports.err
+	}
+	defer imports.Close()
+
+	for _, areaFile := range AreaFiles {
+		area, err := LoadArea(areaFile)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM areas WHERE uuid=?", area.UUID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("failed to query area count: %v", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		err = seedArea(db, imports, area)
+		if err != nil {
+			return fmt.Errorf("error seeding %s: %v", areaFile, err)
+		}
+	}
+	return nil
+}
+
+// seedMobTemplates makes sure every mob the area uses has a template, before
+// any of the area is written, so a missing mob doesn't leave half an area.
+func seedMobTemplates(db *sqlx.DB, imports *mobImports, area *AreaImport) error {
+	for _, reset := range area.Resets {
+		if reset.Type != "mob" {
+			continue
+		}
+		err := seedMobTemplate(db, imports, reset.Slug)
+		if err != nil {
+			return err
+		}
+	}
+	for _, room := range area.Rooms {
+		for _, slug := range room.Mobs {
+			err := seedMobTemplate(db, imports, slug)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func seedArea(db *sqlx.DB, imports *mobImports, area *AreaImport) error {
+	err := seedMobTemplates(db, imports, area)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("INSERT INTO areas (uuid, name, description, reset_interval) VALUES (?, ?, ?, ?)",
+		area.UUID, area.Name, area.Description, area.ResetInterval)
+	if err != nil {
+		return fmt.Errorf("failed to insert area: %v", err)
+	}
+
+	for _, reset := range area.Resets {
+		target := reset.Template
+		if reset.Type == "mob" {
+			target = reset.Slug
+		}
+		maximum := reset.Max
+		if maximum < 1 {
+			maximum = 1
+		}
+		_, err := db.Exec("INSERT INTO area_resets (area_uuid, room_uuid, type, target, max, behaviors) VALUES (?, ?, ?, ?, ?, ?)",
+			area.UUID, reset.Room, reset.Type, target, maximum, strings.Join(area.MobBehaviors[target], ","))
+		if err != nil {
+			return fmt.Errorf("failed to insert area reset: %v", err)
+		}
+	}
+
+	for _, room := range area.Rooms {
+		_, err := db.Exec("INSERT INTO rooms (uuid, area_uuid, name, description, exit_north, exit_south, exit_west, exit_east, exit_up, exit_down) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			room.UUID, area.UUID, room.Name, room.Description, room.Exits["north"], room.Exits["south"], room.Exits["west"], room.Exits["east"], room.Exits["up"], room.Exits["down"])
+		if err != nil {
+			return fmt.Errorf("failed to insert room: %v", err)
+		}
+		for _, slug := range room.Mobs {
+			mob, err := mobs.NewMobFromTemplate(db, slug, area.UUID, room.UUID)
+			if err != nil {
+				return err
+			}
+			if behaviors := area.MobBehaviors[slug]; len(behaviors) > 0 {
+				err = mob.SetBehaviors(db, behaviors)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package seed
+
+import (
+	"testing"
+)
+
+func TestSeedAreasAndRooms(t *testing.T) {
+	db, cfg := openTestDB(t)
+
+	err := Areas(db, cfg)
+	if err != nil {
+		t.Errorf("Areas() returned error: %v", err)
+	}
+}
@@ -1,14 +1,14 @@
-package main
+package seed
 
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
+	"mud/config"
+
 	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 type ClassArchetypeImport struct {
@@ -45,11 +45,7 @@ type ClassImport struct {
 	DocumentUrl               string                 `json:"document__url" db:"document_url"`
 }
 
-func getHpAtFirstLevel(value interface{}) int {
-	hpAtFirstLevelStr, ok := value.(string)
-	if !ok {
-		log.Fatalf("error asserting hp_at_first_level to string")
-	}
+func getHpAtFirstLevel(hpAtFirstLevelStr string) int {
 	hpAtFirstLevel, err := strconv.Atoi(strings.Fields(hpAtFirstLevelStr)[0])
 	if err != nil {
 		fmt.Println("Error converting string to int: ", err)
@@ -57,46 +53,31 @@ func getHpAtFirstLevel(value interface{}) int {
 	return hpAtFirstLevel
 }
 
-func SeedClasses(dbPath string, classesImportDbPath string) error {
-	db, err := sqlx.Open("sqlite3", dbPath)
-	if err != nil {
-		log.Fatalf("Failed to open Sqlite database: %v", err)
-	} else {
-		err := db.Ping()
-		if err != nil {
-			log.Fatalf("Failed to ping database: %v", err)
-		}
-		fmt.Println("Mud database opened successfully")
-	}
-	defer db.Close()
-
-	classesDB, err := sqlx.Connect("sqlite3", classesImportDbPath)
+// Classes adds every class archetype in the class imports database.
+func Classes(db *sqlx.DB, cfg *config.Config) error {
+	classesDB, err := openImports(cfg.Database.ClassImportsPath)
 	if err != nil {
-		log.Fatalf("Failed to open Class Imports database: %v", err)
-	} else {
-		err := db.Ping()
-		if err != nil {
-			log.Fatalf("Failed to ping database Class Imports: %v", err)
-		}
-		fmt.Println("Class Imports Database opened successfully")
+		return err
 	}
 	defer classesDB.Close()
+
 	query := `SELECT description, hit_dice, hp_at_first_level, hp_at_higher_levels, name, slug, proficiencies_saving_throws, archetypes from class_imports;`
 	rows, err := classesDB.Queryx(query)
 	if err != nil {
-		log.Fatalf("Failed to query row: %v", err)
+		return fmt.Errorf("failed to query class imports: %v", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var ci ClassImport
 		err = rows.StructScan(&ci)
 		if err != nil {
-			log.Fatalf("Failed to scan row: %v", err)
+			return fmt.Errorf("failed to scan class import: %v", err)
 		}
 		var archetypes []ClassArchetypeImport
 		err = json.Unmarshal([]byte(ci.ArchetypesData), &archetypes)
 		if err != nil {
-			log.Fatalf("Failed to unmarshal JSON: %v", err)
+			return fmt.Errorf("failed to read %s's archetypes: %v", ci.Name, err)
 		}
 
 		ci.Archetypes = archetypes
@@ -148,16 +129,10 @@ func SeedClasses(dbPath string, classesImportDbPath string) error {
 			archetypeDescription = strings.TrimSpace(archetypeDescription)
 			_, err = db.Exec(queryString, ci.HitDice, hpAtFirstLevel, hpModifier, ci.Name, savingThrowCharisma, savingThrowConstitution, savingThrowDexterity, savingThrowIntelligence, savingThrowStrength, savingThrowWisdom, ci.Slug, archetype.Slug, archetype.Name, archetypeDescription)
 			if err != nil {
-				log.Fatalf("Failed to insert SQLite row: %v", err)
+				return fmt.Errorf("failed to insert class %s: %v", archetype.Slug, err)
 			}
 		}
 	}
 
-	return nil
-}
-
-func main() {
-	dbPath := "./sql_database/mud.db"
-	classesImportDbPath := "./sql_database/class_imports.db"
-	SeedClasses(dbPath, classesImportDbPath)
+	return rows.Err()
 }
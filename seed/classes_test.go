@@ -0,0 +1,14 @@
+package seed
+
+import (
+	"testing"
+)
+
+func TestSeedClasses(t *testing.T) {
+	db, cfg := openTestDB(t)
+
+	err := Classes(db, cfg)
+	if err != nil {
+		t.Errorf("Classes() returned error: %v", err)
+	}
+}
@@ -1,33 +1,21 @@
-package main
+package seed
 
 import (
-	"database/sql"
 	"fmt"
-	"log"
-	"mud/display"
 
-	_ "github.com/mattn/go-sqlite3"
+	"mud/config"
+	"mud/display"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
-func SeedColorProfiles() {
-	db, err := sql.Open("sqlite3", "./sql_database/mud.db")
-	if err != nil {
-		log.Fatalf("Failed to open SQLite database: %v", err)
-	} else {
-		err := db.Ping()
-		if err != nil {
-			log.Fatalf("Failed to ping database: %v", err)
-		}
-		fmt.Println("Database opened successfully")
-	}
-
-	defer db.Close()
-
+// ColorProfiles adds the light and dark color profiles players can choose
+// between.
+func ColorProfiles(db *sqlx.DB, cfg *config.Config) error {
 	var colorProfiles = map[string]map[string]string{
 		"Light Mode": {
-			"uuid":              "2c7dfd5b-d160-42e0-accb-b77d9686dbea", // this is a hack.  same as in login-logout.go
+			"uuid":              cfg.Players.DefaultColorProfileUUID, // new characters start out with this one
 			"primary_color":     display.BrightGreen,
 			"secondary_color":   display.Green,
 			"warning_color":     display.BrightYellow,
@@ -53,12 +41,8 @@ func SeedColorProfiles() {
 		`, colors["uuid"], name, colors["primary_color"], colors["secondary_color"], colors["warning_color"], colors["danger_color"], colors["title_color"], colors["description_color"])
 
 		if err != nil {
-			log.Fatalf("Failed to seed Color Profiles:  %v", err)
+			return fmt.Errorf("failed to seed color profile %s: %v", name, err)
 		}
 	}
-
-}
-
-func main() {
-	SeedColorProfiles()
+	return nil
 }
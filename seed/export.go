@@ -0,0 +1,196 @@
+package seed
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"mud/config"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type roomExport struct {
+	UUID        string         `db:"uuid"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	North       sql.NullString `db:"exit_north"`
+	South       sql.NullString `db:"exit_south"`
+	East        sql.NullString `db:"exit_east"`
+	West        sql.NullString `db:"exit_west"`
+	Up          sql.NullString `db:"exit_up"`
+	Down        sql.NullString `db:"exit_down"`
+}
+
+func (room roomExport) exits() map[string]string {
+	exits := make(map[string]string)
+	for direction, exit := range map[string]sql.NullString{
+		"north": room.North, "south": room.South, "east": room.East,
+		"west": room.West, "up": room.Up, "down": room.Down,
+	} {
+		if exit.Valid && exit.String != "" {
+			exits[direction] = exit.String
+		}
+	}
+	if len(exits) == 0 {
+		return nil
+	}
+	return exits
+}
+
+// ExportAreas reads every area back out of the database in the same shape as
+// the area files, so that changes made in game can be kept.  Mobs are the ones
+// in each room right now, items on the floor aren't exported as they no longer
+// know which template they came from.
+func ExportAreas(db *sqlx.DB) ([]*AreaImport, error) {
+	var areas []*AreaImport
+	rows, err := db.Queryx("SELECT uuid, name, description, reset_interval FROM areas ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching areas: %v", err)
+	}
+	for rows.Next() {
+		var area AreaImport
+		var name, description sql.NullString
+		var resetInterval sql.NullInt64
+		err = rows.Scan(&area.UUID, &name, &description, &resetInterval)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning area: %v", err)
+		}
+		area.Name = name.String
+		area.Description = description.String
+		area.ResetInterval = int(resetInterval.Int64)
+		areas = append(areas, &area)
+	}
+	rows.Close()
+
+	for _, area := range areas {
+		err = exportArea(db, area)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting %s: %v", area.Name, err)
+		}
+	}
+	return areas, nil
+}
+
+func exportArea(db *sqlx.DB, area *AreaImport) error {
+	behaviors := make(map[string][]string)
+	addBehaviors := func(slug string, list string) {
+		if list != "" && behaviors[slug] == nil {
+			behaviors[slug] = strings.Split(list, ",")
+		}
+	}
+
+	var resets []struct {
+		Room      string `db:"room_uuid"`
+		Type      string `db:"type"`
+		Target    string `db:"target"`
+		Max       int    `db:"max"`
+		Behaviors string `db:"behaviors"`
+	}
+	err := db.Select(&resets, "SELECT room_uuid, type, target, max, behaviors FROM area_resets WHERE area_uuid = ? ORDER BY id", area.UUID)
+	if err != nil {
+		return fmt.Errorf("error fetching resets: %v", err)
+	}
+	for _, reset := range resets {
+		resetImport := ResetImport{Type: reset.Type, Room: reset.Room, Max: reset.Max}
+		if reset.Type == "mob" {
+			resetImport.Slug = reset.Target
+			addBehaviors(reset.Target, reset.Behaviors)
+		} else {
+			resetImport.Template = reset.Target
+		}
+		area.Resets = append(area.Resets, resetImport)
+	}
+
+	var rooms []roomExport
+	err = db.Select(&rooms, "SELECT uuid, name, description, exit_north, exit_south, exit_east, exit_west, exit_up, exit_down FROM rooms WHERE area_uuid = ? ORDER BY rowid", area.UUID)
+	if err != nil {
+		return fmt.Errorf("error fetching rooms: %v", err)
+	}
+	for _, room := range rooms {
+		roomImport := RoomImport{
+			UUID:        room.UUID,
+			Name:        room.Name,
+			Description: room.Description,
+			Exits:       room.exits(),
+		}
+
+		var roomMobs []struct {
+			Slug      string `db:"slug"`
+			Behaviors string `db:"behaviors"`
+		}
+		err = db.Select(&roomMobs, "SELECT slug, behaviors FROM mobs WHERE room_uuid = ? ORDER BY id", room.UUID)
+		if err != nil {
+			return fmt.Errorf("error fetching mobs in %s: %v", room.Name, err)
+		}
+		for _, mob := range roomMobs {
+			roomImport.Mobs = append(roomImport.Mobs, mob.Slug)
+			addBehaviors(mob.Slug, mob.Behaviors)
+		}
+		area.Rooms = append(area.Rooms, roomImport)
+	}
+
+	if len(behaviors) > 0 {
+		area.MobBehaviors = behaviors
+	}
+	return nil
+}
+
+// ImportAreas reads the area files into the database.  An area which is
+// already there is an error unless replace is set, in which case its rooms,
+// mobs and resets are thrown away first.  The monster imports database is only
+// needed for mobs which aren't in mob_templates yet.
+func ImportAreas(db *sqlx.DB, cfg *config.Config, paths []string, replace bool) error {
+	imports := openMobImports(cfg)
+	defer imports.Close()
+
+	for _, path := range paths {
+		area, err := LoadArea(path)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM areas WHERE uuid=?", area.UUID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("failed to query area count: %v", err)
+		}
+		if count > 0 {
+			if !replace {
+				return fmt.Errorf("%s: area %s is already in the database", path, area.Name)
+			}
+			err = deleteArea(db, area.UUID)
+			if err != nil {
+				return fmt.Errorf("error removing %s: %v", area.Name, err)
+			}
+		}
+
+		err = seedArea(db, imports, area)
+		if err != nil {
+			return fmt.Errorf("error importing %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func deleteArea(db *sqlx.DB, areaUUID string) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM mobs WHERE area_uuid = ?",
+		"DELETE FROM area_resets WHERE area_uuid = ?",
+		"DELETE FROM rooms WHERE area_uuid = ?",
+		"DELETE FROM areas WHERE uuid = ?",
+	} {
+		_, err = tx.Exec(query, areaUUID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
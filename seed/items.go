@@ -0,0 +1,61 @@
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"mud/config"
+
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/yaml.v2"
+)
+
+// ItemFiles are the item templates areas can reset.
+var ItemFiles = []string{"areas/seeds/arena_items.yml"}
+
+type ItemImport struct {
+	UUID           string   `yaml:"uuid"`
+	Name           string   `yaml:"name"`
+	Description    string   `yaml:"description"`
+	EquipmentSlots []string `yaml:"equipment_slots"`
+}
+
+// LoadItems reads an item templates file.
+func LoadItems(path string) ([]ItemImport, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var itemTemplates []ItemImport
+	err = yaml.Unmarshal(file, &itemTemplates)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	return itemTemplates, nil
+}
+
+// Items adds the item templates in ItemFiles, leaving any which are already
+// there alone.
+func Items(db *sqlx.DB, cfg *config.Config) error {
+	for _, itemFile := range ItemFiles {
+		itemTemplates, err := LoadItems(itemFile)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range itemTemplates {
+			equipmentSlotsJSON, err := json.Marshal(item.EquipmentSlots)
+			if err != nil {
+				return err
+			}
+			_, err = db.Exec("INSERT OR IGNORE INTO item_templates (uuid, name, description, equipment_slots) VALUES (?, ?, ?, ?)",
+				item.UUID, item.Name, item.Description, string(equipmentSlotsJSON))
+			if err != nil {
+				return fmt.Errorf("failed to insert item %s: %v", item.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,12 +1,12 @@
-package main
+package seed
 
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+
+	"mud/config"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 type SubraceImport struct {
@@ -32,54 +32,36 @@ type RaceImport struct {
 	Subraces    []SubraceImport
 }
 
-func SeedRaces(dbPath string, racesImportDbPath string) error {
-	db, err := sqlx.Open("sqlite3", dbPath)
-	if err != nil {
-		log.Fatalf("Failed to open Sqlite database: %v", err)
-	} else {
-		err := db.Ping()
-		if err != nil {
-			log.Fatalf("Failed to ping database: %v", err)
-		}
-		fmt.Println("Mud database opened successfully")
-	}
-	defer db.Close()
-
-	racesDB, err := sqlx.Connect("sqlite3", racesImportDbPath)
+// Races adds every race and subrace in the race imports database.
+func Races(db *sqlx.DB, cfg *config.Config) error {
+	racesDB, err := openImports(cfg.Database.RaceImportsPath)
 	if err != nil {
-		log.Fatalf("Failed to open Class Imports database: %v", err)
-	} else {
-		err := db.Ping()
-		if err != nil {
-			log.Fatalf("Failed to ping database Class Imports: %v", err)
-		}
-		fmt.Println("Class Imports Database opened successfully")
+		return err
 	}
 	defer racesDB.Close()
 
 	query := `SELECT name, slug, size_raw, subraces, description, asi from race_imports;`
 	rows, err := racesDB.Queryx(query)
 	if err != nil {
-		log.Fatalf("Failed to query row: %v", err)
+		return fmt.Errorf("failed to query race imports: %v", err)
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var ri RaceImport
 		err = rows.StructScan(&ri)
 		if err != nil {
-			log.Fatalf("Failed to scan row: %v", err)
+			return fmt.Errorf("failed to scan race import: %v", err)
 		}
 		var asi []Asi
 		err = json.Unmarshal([]byte(ri.ASIData), &asi)
 		if err != nil {
-			fmt.Println(err)
-			log.Fatalf("Failed to unmarshal JSON: %v", err)
+			return fmt.Errorf("failed to read %s's ability score increases: %v", ri.Name, err)
 		}
 		ri.ASI = asi
 		var subraces []SubraceImport
 		err = json.Unmarshal([]byte(ri.SubraceData), &subraces)
 		if err != nil {
-			fmt.Println(err)
-			log.Fatalf("Failed to unmarshal JSON: %v", err)
+			return fmt.Errorf("failed to read %s's subraces: %v", ri.Name, err)
 		}
 		ri.Subraces = subraces
 		// need to write each record into the game db now.
@@ -91,27 +73,21 @@ func SeedRaces(dbPath string, racesImportDbPath string) error {
 		if len(subraces) == 0 {
 			_, err = db.Exec(queryString, ri.Name, ri.Slug, ri.Size, ri.Description, ri.ASIData, "", "", "")
 			if err != nil {
-				log.Fatalf("Failed to insert into races table: %v", err)
+				return fmt.Errorf("failed to insert race %s: %v", ri.Slug, err)
 			}
 		} else {
 			for _, subrace := range subraces {
 				asi, err := json.Marshal(subrace.ASI)
 				if err != nil {
-					log.Fatalf("Failed to marshal ASI into json: %v", err)
+					return fmt.Errorf("failed to marshal ASI into json: %v", err)
 				}
 
 				_, err = db.Exec(queryString, ri.Name, ri.Slug, ri.Size, ri.Description, asi, subrace.Name, subrace.Slug, subrace.Description)
 				if err != nil {
-					log.Fatalf("Failed to insert into races table: %v", err)
+					return fmt.Errorf("failed to insert race %s: %v", ri.Slug, err)
 				}
 			}
 		}
 	}
-	return nil
-}
-
-func main() {
-	dbPath := "./sql_database/mud.db"
-	racesImportDbPath := "./sql_database/race_imports.db"
-	SeedRaces(dbPath, racesImportDbPath)
+	return rows.Err()
 }
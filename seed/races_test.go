@@ -0,0 +1,14 @@
+package seed
+
+import (
+	"testing"
+)
+
+func TestSeedRaces(t *testing.T) {
+	db, cfg := openTestDB(t)
+
+	err := Races(db, cfg)
+	if err != nil {
+		t.Errorf("Races() returned error: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Package seed fills a freshly migrated database with the world and the data
+// imported from the SRD.
+package seed
+
+import (
+	"fmt"
+	"os"
+
+	"mud/config"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// A Seeder adds one kind of data to the database.
+type Seeder func(db *sqlx.DB, cfg *config.Config) error
+
+// Seeders by the name `mud seed` knows them by.
+var Seeders = map[string]Seeder{
+	"items":   Items,
+	"areas":   Areas,
+	"display": ColorProfiles,
+	"players": Players,
+	"classes": Classes,
+	"races":   Races,
+}
+
+// Order is the order `mud seed` runs every seeder in, item templates have to
+// be there before the areas which reset them, and color profiles before the
+// players who use them.
+var Order = []string{"items", "areas", "display", "players", "classes", "races"}
+
+// openImports opens one of the SRD import databases.  They're only read, and
+// opening them read only stops a mistyped path from leaving an empty database
+// behind.
+func openImports(path string) (*sqlx.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("error opening imports database: %v", err)
+	}
+	return sqlx.Connect("sqlite3", "file:"+path+"?mode=ro")
+}
@@ -0,0 +1,45 @@
+package seed
+
+import (
+	"os"
+	"testing"
+
+	"mud/config"
+	"mud/migrations"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// chdirRoot moves to the root of the repo for the rest of the test, so that
+// the seed files and import databases can be found.
+func chdirRoot(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// openTestDB migrates a fresh test database, from the root of the repo.
+func openTestDB(t *testing.T) (*sqlx.DB, *config.Config) {
+	chdirRoot(t)
+
+	cfg := config.Default()
+	// delete the old test db
+	_ = os.Remove(cfg.Database.TestPath)
+	db, err := sqlx.Connect("sqlite3", cfg.Database.TestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = migrations.Up(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, cfg
+}
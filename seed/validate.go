@@ -0,0 +1,420 @@
+package seed
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MobFiles are the hand written mobs, which aren't seeded yet but share their
+// uuids with everything else.
+var MobFiles = []string{"areas/seeds/arena_mobs.yml"}
+
+// Severity is how bad a problem with the world files is.  Warnings are things
+// a builder might have meant, like a one-way exit.
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// A Diagnostic is one problem found in the world files.
+type Diagnostic struct {
+	File     string
+	Line     int
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", d.File, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", d.File, d.Line, d.Severity, d.Message)
+}
+
+// opposites are the exits which should lead back, ie north from one room
+// means south from the next.
+var opposites = map[string]string{
+	"north": "south", "south": "north",
+	"east": "west", "west": "east",
+	"up": "down", "down": "up",
+}
+
+// exitOrder is the order a room's exits are checked in, so diagnostics come
+// out the same every time.
+var exitOrder = []string{"north", "south", "east", "west", "up", "down"}
+
+type location struct {
+	file string
+	line int
+}
+
+type exitInfo struct {
+	target string
+	location
+}
+
+type roomInfo struct {
+	location
+	name  string
+	exits map[string]exitInfo
+}
+
+// A reference is a room, mob slug or item template named somewhere in the
+// world files, which has to exist.
+type reference struct {
+	name string
+	location
+}
+
+// worldCheck is everything read from the world files, for checking once all
+// of them have been read.
+type worldCheck struct {
+	diagnostics []Diagnostic
+	uuids       map[string]location
+	rooms       map[string]*roomInfo
+	roomOrder   []string
+	roomRefs    []reference
+	mobRefs     []reference
+	itemRefs    []reference
+	items       map[string]bool
+}
+
+func (c *worldCheck) report(at location, severity Severity, format string, args ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		File:     at.file,
+		Line:     at.line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// define records a uuid, reporting it if something else already has it.
+func (c *worldCheck) define(uuid string, at location) bool {
+	if uuid == "" {
+		c.report(at, Error, "missing uuid")
+		return false
+	}
+	if first, ok := c.uuids[uuid]; ok {
+		c.report(at, Error, "duplicate uuid %s, already used at %s:%d", uuid, first.file, first.line)
+		return false
+	}
+	c.uuids[uuid] = at
+	return true
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// loadNode reads a YAML file, keeping the line numbers which decoding into a
+// struct would lose.
+func (c *worldCheck) loadNode(path string) *yaml.Node {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		c.report(location{file: path}, Error, "%v", err)
+		return nil
+	}
+
+	var document yaml.Node
+	err = yaml.Unmarshal(file, &document)
+	if err != nil {
+		at := location{file: path}
+		message := err.Error()
+		if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+			at.line, _ = strconv.Atoi(match[1])
+			message = match[2]
+		}
+		c.report(at, Error, "%s", message)
+		return nil
+	}
+	if len(document.Content) == 0 {
+		c.report(location{file: path}, Error, "file is empty")
+		return nil
+	}
+	return document.Content[0]
+}
+
+// field returns the value of the key in a mapping, or nil.
+func field(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func value(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return node.Value
+}
+
+func at(path string, node *yaml.Node) location {
+	return location{file: path, line: node.Line}
+}
+
+// sequence returns the items of a list, reporting anything else.
+func (c *worldCheck) sequence(path string, node *yaml.Node, what string) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		c.report(at(path, node), Error, "%s should be a list", what)
+		return nil
+	}
+	return node.Content
+}
+
+func (c *worldCheck) readArea(path string) {
+	area := c.loadNode(path)
+	if area == nil {
+		return
+	}
+	if area.Kind != yaml.MappingNode {
+		c.report(at(path, area), Error, "an area file should be a mapping")
+		return
+	}
+	c.define(value(field(area, "uuid")), at(path, area))
+
+	for _, room := range c.sequence(path, field(area, "rooms"), "rooms") {
+		uuid := value(field(room, "uuid"))
+		info := &roomInfo{
+			location: at(path, room),
+			name:     value(field(room, "name")),
+			exits:    make(map[string]exitInfo),
+		}
+		if info.name == "" {
+			c.report(info.location, Error, "room %s has no name", uuid)
+		}
+		if !c.define(uuid, info.location) {
+			continue
+		}
+
+		if exits := field(room, "exits"); exits != nil {
+			if exits.Kind != yaml.MappingNode {
+				c.report(at(path, exits), Error, "exits should be a mapping of direction to room uuid")
+			} else {
+				for i := 0; i+1 < len(exits.Content); i += 2 {
+					direction, target := exits.Content[i], exits.Content[i+1]
+					if _, ok := opposites[direction.Value]; !ok {
+						c.report(at(path, direction), Error, "unknown direction %q in %s", direction.Value, info.name)
+						continue
+					}
+					exit := exitInfo{target: target.Value, location: at(path, target)}
+					info.exits[direction.Value] = exit
+					c.roomRefs = append(c.roomRefs, reference{name: exit.target, location: exit.location})
+				}
+			}
+		}
+
+		for _, mob := range c.sequence(path, field(room, "mobs"), "mobs") {
+			c.mobRefs = append(c.mobRefs, reference{name: mob.Value, location: at(path, mob)})
+		}
+		for _, item := range c.sequence(path, field(room, "items"), "items") {
+			c.itemRefs = append(c.itemRefs, reference{name: item.Value, location: at(path, item)})
+		}
+
+		c.rooms[uuid] = info
+		c.roomOrder = append(c.roomOrder, uuid)
+	}
+
+	for _, reset := range c.sequence(path, field(area, "resets"), "resets") {
+		room := field(reset, "room")
+		if room == nil {
+			c.report(at(path, reset), Error, "reset has no room")
+		} else {
+			c.roomRefs = append(c.roomRefs, reference{name: room.Value, location: at(path, room)})
+		}
+
+		switch kind := value(field(reset, "type")); kind {
+		case "mob":
+			if slug := field(reset, "slug"); slug != nil {
+				c.mobRefs = append(c.mobRefs, reference{name: slug.Value, location: at(path, slug)})
+			} else {
+				c.report(at(path, reset), Error, "mob reset has no slug")
+			}
+		case "item":
+			if template := field(reset, "template"); template != nil {
+				c.itemRefs = append(c.itemRefs, reference{name: template.Value, location: at(path, template)})
+			} else {
+				c.report(at(path, reset), Error, "item reset has no template")
+			}
+		default:
+			c.report(at(path, reset), Error, "unknown reset type %q, should be mob or item", kind)
+		}
+	}
+
+	if behaviors := field(area, "mob_behaviors"); behaviors != nil && behaviors.Kind == yaml.MappingNode {
+		for i := 0; i < len(behaviors.Content); i += 2 {
+			slug := behaviors.Content[i]
+			c.mobRefs = append(c.mobRefs, reference{name: slug.Value, location: at(path, slug)})
+		}
+	}
+}
+
+// readList reads a file which is a list of things with uuids, ie item
+// templates.
+func (c *worldCheck) readList(path string) []string {
+	list := c.loadNode(path)
+	if list == nil {
+		return nil
+	}
+
+	var uuids []string
+	for _, entry := range c.sequence(path, list, "the file") {
+		uuid := value(field(entry, "uuid"))
+		if c.define(uuid, at(path, entry)) {
+			uuids = append(uuids, uuid)
+		}
+		if value(field(entry, "name")) == "" {
+			c.report(at(path, entry), Error, "%s has no name", uuid)
+		}
+	}
+	return uuids
+}
+
+// checkExits reports exits to rooms which don't exist, and exits which don't
+// lead back the way they came.
+func (c *worldCheck) checkExits() {
+	for _, ref := range c.roomRefs {
+		if _, ok := c.rooms[ref.name]; !ok {
+			c.report(ref.location, Error, "no room has the uuid %q", ref.name)
+		}
+	}
+
+	for _, uuid := range c.roomOrder {
+		room := c.rooms[uuid]
+		for _, direction := range exitOrder {
+			exit, ok := room.exits[direction]
+			if !ok {
+				continue
+			}
+			target, ok := c.rooms[exit.target]
+			if !ok {
+				continue
+			}
+			back, ok := target.exits[opposites[direction]]
+			switch {
+			case !ok:
+				c.report(exit.location, Warning, "one-way exit, %s leads %s to %s which has no %s exit",
+					room.name, direction, target.name, opposites[direction])
+			case back.target != uuid:
+				c.report(exit.location, Warning, "%s leads %s to %s, but %s from there leads somewhere else",
+					room.name, direction, target.name, opposites[direction])
+			}
+		}
+	}
+}
+
+// checkReachable reports rooms which can't be walked to from the start room.
+func (c *worldCheck) checkReachable(startRoom string) {
+	if _, ok := c.rooms[startRoom]; !ok {
+		c.report(location{file: "config"}, Error, "the start room %s isn't in any area", startRoom)
+		return
+	}
+
+	reached := map[string]bool{startRoom: true}
+	queue := []string{startRoom}
+	for len(queue) > 0 {
+		room := c.rooms[queue[0]]
+		queue = queue[1:]
+		for _, exit := range room.exits {
+			if _, ok := c.rooms[exit.target]; ok && !reached[exit.target] {
+				reached[exit.target] = true
+				queue = append(queue, exit.target)
+			}
+		}
+	}
+
+	for _, uuid := range c.roomOrder {
+		if !reached[uuid] {
+			room := c.rooms[uuid]
+			c.report(room.location, Warning, "%s can't be reached from the start room", room.name)
+		}
+	}
+}
+
+// ValidateWorld checks the area, item and mob files which make up the world,
+// and returns what's wrong with them, ordered by file and line.  Mob slugs are
+// checked against knownMobs, unless it's nil.
+func ValidateWorld(startRoom string, knownMobs map[string]bool) []Diagnostic {
+	check := &worldCheck{
+		uuids: make(map[string]location),
+		rooms: make(map[string]*roomInfo),
+		items: make(map[string]bool),
+	}
+
+	for _, path := range AreaFiles {
+		check.readArea(path)
+	}
+	for _, path := range ItemFiles {
+		for _, uuid := range check.readList(path) {
+			check.items[uuid] = true
+		}
+	}
+	for _, path := range MobFiles {
+		check.readList(path)
+	}
+
+	check.checkExits()
+	check.checkReachable(startRoom)
+	for _, ref := range check.itemRefs {
+		if !check.items[ref.name] {
+			check.report(ref.location, Error, "no item template has the uuid %q", ref.name)
+		}
+	}
+	if knownMobs != nil {
+		for _, ref := range check.mobRefs {
+			if !knownMobs[ref.name] {
+				check.report(ref.location, Error, "unknown mob %q", ref.name)
+			}
+		}
+	}
+
+	sort.SliceStable(check.diagnostics, func(i, j int) bool {
+		a, b := check.diagnostics[i], check.diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return check.diagnostics
+}
+
+// KnownMobs lists the slugs in the monster imports database, which is what
+// the areas can use.
+func KnownMobs(path string) (map[string]bool, error) {
+	monstersDB, err := openImports(path)
+	if err != nil {
+		return nil, err
+	}
+	defer monstersDB.Close()
+
+	var slugs []string
+	err = monstersDB.Select(&slugs, "SELECT slug FROM mob_imports")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching mob slugs: %v", err)
+	}
+	known := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		known[slug] = true
+	}
+	return known, nil
+}
@@ -0,0 +1,122 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mud/config"
+)
+
+// TestWorldFiles fails on anything wrong with the world files, warnings like
+// one-way exits are only logged as they can be meant.
+func TestWorldFiles(t *testing.T) {
+	chdirRoot(t)
+	cfg := config.Default()
+
+	knownMobs, err := KnownMobs(cfg.Database.MonsterImportsPath)
+	if err != nil {
+		t.Logf("not checking mob slugs: %v", err)
+	}
+	for _, diagnostic := range ValidateWorld(cfg.World.StartRoomUUID, knownMobs) {
+		if diagnostic.Severity == Error {
+			t.Error(diagnostic)
+		} else {
+			t.Log(diagnostic)
+		}
+	}
+}
+
+func writeWorldFile(t *testing.T, dir string, name string, contents string) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(strings.TrimLeft(contents, "\n")), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateWorldFindsProblems(t *testing.T) {
+	dir := t.TempDir()
+	area := writeWorldFile(t, dir, "area.yml", `
+uuid: area-1
+name: Test Area
+resets:
+  - type: mob
+    slug: goblin
+    room: room-1
+  - type: item
+    template: item-2
+    room: room-9
+rooms:
+  - uuid: room-1
+    name: Start
+    exits:
+      north: room-2
+      east: room-3
+  - uuid: room-2
+    name: North
+    exits:
+      west: room-1
+      sideways: room-1
+  - uuid: room-3
+    name: East
+    mobs:
+      - aboleth
+    exits:
+      east: room-missing
+  - uuid: room-4
+    name: Nowhere
+  - uuid: room-1
+    name: Copy
+`)
+	items := writeWorldFile(t, dir, "items.yml", `
+- uuid: item-1
+  name: sword
+`)
+
+	oldAreas, oldItems, oldMobs := AreaFiles, ItemFiles, MobFiles
+	AreaFiles, ItemFiles, MobFiles = []string{area}, []string{items}, nil
+	defer func() { AreaFiles, ItemFiles, MobFiles = oldAreas, oldItems, oldMobs }()
+
+	var got []string
+	for _, diagnostic := range ValidateWorld("room-1", map[string]bool{"aboleth": true}) {
+		got = append(got, strings.TrimPrefix(diagnostic.String(), area))
+	}
+
+	expected := []string{
+		":5: error: unknown mob \"goblin\"",
+		":8: error: no item template has the uuid \"item-2\"",
+		":9: error: no room has the uuid \"room-9\"",
+		":14: warning: one-way exit, Start leads north to North which has no south exit",
+		":15: warning: one-way exit, Start leads east to East which has no west exit",
+		":19: warning: North leads west to Start, but east from there leads somewhere else",
+		":20: error: unknown direction \"sideways\" in North",
+		":26: error: no room has the uuid \"room-missing\"",
+		":27: warning: Nowhere can't be reached from the start room",
+		":29: error: duplicate uuid room-1, already used at " + area + ":11",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestValidateWorldReportsYAMLErrors(t *testing.T) {
+	dir := t.TempDir()
+	area := writeWorldFile(t, dir, "area.yml", `
+uuid: area-1
+rooms:
+  - uuid: room-1
+    name: [unclosed
+`)
+
+	oldAreas, oldItems, oldMobs := AreaFiles, ItemFiles, MobFiles
+	AreaFiles, ItemFiles, MobFiles = []string{area}, nil, nil
+	defer func() { AreaFiles, ItemFiles, MobFiles = oldAreas, oldItems, oldMobs }()
+
+	diagnostics := ValidateWorld("room-1", nil)
+	if len(diagnostics) == 0 || diagnostics[0].File != area || diagnostics[0].Line == 0 {
+		t.Errorf("expected a syntax error with a line number, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mud/areas"
+	"mud/commands"
+	"mud/config"
+	"mud/notifications"
+	"mud/players"
+	"mud/transport"
+	"mud/world_state"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/jmoiron/sqlx"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// runServe runs the game until it is shut down, by a signal or an admin.
+func runServe(args []string) error {
+	flags, configPath := newFlagSet("serve")
+	defaults := config.Default()
+	respawnRoom := flags.String("respawn-room", defaults.World.StartRoomUUID, "uuid of the room players wake up in after dying")
+	telnetAddress := flags.String("telnet", defaults.Server.TelnetAddress, "address to listen for telnet connections on, ie :4000.  telnet is off when empty")
+	idleTimeout := flags.Duration("idle-timeout", defaults.Server.IdleTimeout, "how long players can be idle before being disconnected, 0 to never disconnect them")
+	duplicateLogin := flags.String("duplicate-login", defaults.Server.DuplicateLogin, "what to do when a character who is playing logs in again: takeover the old connection, or refuse the new one")
+	maxCharacters := flags.Int("max-characters", defaults.Players.MaxCharacters, "how many characters an account may have")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usage("serve")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	// flags given on the command line win over the config
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "respawn-room":
+			cfg.World.RespawnRoomUUID = *respawnRoom
+		case "telnet":
+			cfg.Server.TelnetAddress = *telnetAddress
+		case "idle-timeout":
+			cfg.Server.IdleTimeout = *idleTimeout
+		case "duplicate-login":
+			cfg.Server.DuplicateLogin = *duplicateLogin
+		case "max-characters":
+			cfg.Players.MaxCharacters = *maxCharacters
+		}
+	})
+	err = cfg.Validate()
+	if err != nil {
+		return err
+	}
+
+	players.StartAreaUUID = cfg.World.StartAreaUUID
+	players.StartRoomUUID = cfg.World.StartRoomUUID
+	players.RespawnRoomUUID = cfg.RespawnRoom()
+	players.DefaultColorProfileUUID = cfg.Players.DefaultColorProfileUUID
+	players.StartingMovement = cfg.Players.StartingMovement
+	players.MaxCharactersPerAccount = cfg.Players.MaxCharacters
+	transport.IdleTimeout = cfg.Server.IdleTimeout
+
+	db, err := openDatabase(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Database opened successfully")
+	err = migrateUp(db)
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	server := NewServer(cfg)
+	notifier := notifications.NewNotifier(server.connections)
+
+	logoutAllPlayers(db)
+	areasCtx, stopAreas := context.WithCancel(context.Background())
+	server.areasDone = areasCtx.Done()
+	// stops whatever has been started when the server can't get going
+	abandon := func(err error) error {
+		stopAreas()
+		server.areaLoops.Wait()
+		db.Close()
+		return err
+	}
+
+	areaInstances, roomToAreaMap, areaChannels, err := loadAreas(areasCtx, db, server, notifier)
+	if err != nil {
+		return abandon(fmt.Errorf("error loading areas: %v", err))
+	}
+	server.areaChannels = areaChannels
+	err = checkStartRooms(cfg, roomToAreaMap)
+	if err != nil {
+		return abandon(err)
+	}
+
+	worldState := world_state.NewWorldState(areaInstances, roomToAreaMap, db)
+	areas.SetPlayerRooms(worldState)
+	sendRoomInfoOnMove(server.connections, worldState)
+	setPromptContext(worldState)
+
+	// the game can go on without help, it just has less to say
+	err = commands.LoadHelpTopics(cfg.World.HelpPath)
+	if err != nil {
+		log.Printf("Error loading help topics: %v", err)
+	}
+
+	s, err := wish.NewServer(
+		wish.WithAddress(cfg.Server.SSHAddress),
+		wish.WithHostKeyPath(cfg.Server.HostKeyPath),
+		// only keys which have been added to a character get in, the rest fall
+		// back to keyboard-interactive.
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
+			return authorizeKey(db, ctx, key)
+		}),
+		// clients without keys still need a way in, LoginPlayer asks them for a
+		// password.  Any keys they offered on the way here weren't proven, so
+		// they don't count.
+		wish.WithKeyboardInteractiveAuth(func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+			delete(ctx.Permissions().Extensions, transport.KeyFingerprintExtension)
+			return true
+		}),
+		wish.WithMiddleware(
+			BubbleteaMUD(db, server, notifier, areaChannels, roomToAreaMap, worldState),
+		),
+	)
+	if err != nil {
+		return abandon(err)
+	}
+
+	// players who stayed connected through a copyover pick up where they were
+	server.resumeCopyover(db, server.newRouter(notifier, worldState), areaChannels, worldState)
+
+	// a listener which can't start takes the server down with it
+	listenErrors := make(chan error, 2)
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	if cfg.Server.TelnetAddress != "" {
+		go func() {
+			log.Printf("Starting telnet server on %s", cfg.Server.TelnetAddress)
+			err := transport.ListenTelnet(listenCtx, cfg.Server.TelnetAddress, func(conn transport.Conn) {
+				server.handleConnection(conn, server.newRouter(notifier, worldState), db, areaChannels, roomToAreaMap, worldState)
+			})
+			if err != nil {
+				listenErrors <- fmt.Errorf("telnet: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		log.Printf("Starting SSH server on %s", cfg.Server.SSHAddress)
+		err := s.ListenAndServe()
+		if err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			listenErrors <- fmt.Errorf("ssh: %v", err)
+		}
+	}()
+
+	// SIGHUP reboots, keeping telnet players connected
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	shutdownRequests := make(chan bool, 1)
+	commands.OnShutdown(func(reboot bool) {
+		select {
+		case shutdownRequests <- reboot:
+		default:
+		}
+	})
+
+	var reboot bool
+	var listenErr error
+	select {
+	case sig := <-signals:
+		log.Printf("Received %s", sig)
+		reboot = sig == syscall.SIGHUP
+	case reboot = <-shutdownRequests:
+	case listenErr = <-listenErrors:
+	}
+
+	err = server.shutdown(db, s, stopListening, stopAreas, reboot)
+	if err != nil {
+		return err
+	}
+	log.Println("Server shut down")
+	return listenErr
+}
+
+// checkStartRooms makes sure new and dead players have somewhere to go.
+func checkStartRooms(cfg *config.Config, roomToAreaMap map[string]string) error {
+	for _, roomUUID := range []string{cfg.World.StartRoomUUID, cfg.RespawnRoom()} {
+		if roomToAreaMap[roomUUID] == "" {
+			return fmt.Errorf("there is no room %s to start or respawn players in", roomUUID)
+		}
+	}
+	return nil
+}
+
+// loadRoomToAreaMap is which area each room is in, for checking the world
+// without loading it all.
+func loadRoomToAreaMap(db *sqlx.DB) (map[string]string, error) {
+	rows, err := db.Query("SELECT uuid, area_uuid FROM rooms")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roomToAreaMap := make(map[string]string)
+	for rows.Next() {
+		var roomUUID, areaUUID string
+		err := rows.Scan(&roomUUID, &areaUUID)
+		if err != nil {
+			return nil, err
+		}
+		roomToAreaMap[roomUUID] = areaUUID
+	}
+	return roomToAreaMap, rows.Err()
+}
+
+// authorizeKey lets in keys which have been added to a character, passing
+// their fingerprint on to LoginPlayer.  The permissions are shared by every
+// attempt on the connection and the key is only checked against its signature
+// after this says yes, so each attempt has to leave behind its own fingerprint
+// or none at all.
+func authorizeKey(db *sqlx.DB, ctx ssh.Context, key ssh.PublicKey) bool {
+	permissions := ctx.Permissions()
+	delete(permissions.Extensions, transport.KeyFingerprintExtension)
+
+	fingerprint := gossh.FingerprintSHA256(key)
+	known, err := players.HasPlayerKey(db, fingerprint)
+	if err != nil {
+		log.Printf("Error looking up key %s: %v", fingerprint, err)
+		return false
+	}
+	if !known {
+		return false
+	}
+
+	if permissions.Extensions == nil {
+		permissions.Extensions = make(map[string]string)
+	}
+	permissions.Extensions[transport.KeyFingerprintExtension] = fingerprint
+	return true
+}
@@ -0,0 +1,165 @@
+package sessions
+
+import (
+	"mud/players"
+	"strings"
+	"sync"
+)
+
+type entry struct {
+	player   *players.Player
+	areaUUID string
+	roomUUID string
+}
+
+// Registry keeps track of who is logged in, and where they are.  It is shared
+// between the ssh sessions, the notifier and every area loop, so everything goes
+// through the lock.  Locations are indexed here rather than read off of the
+// players, since the players are being moved around from other goroutines.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]*entry)}
+}
+
+func (r *Registry) Add(player *players.Player) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[player.UUID] = &entry{player: player, areaUUID: player.AreaUUID, roomUUID: player.RoomUUID}
+}
+
+// AddIfAbsent adds the player unless their character is already logged in.
+func (r *Registry) AddIfAbsent(player *players.Player) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[player.UUID]; ok {
+		return false
+	}
+	r.entries[player.UUID] = &entry{player: player, areaUUID: player.AreaUUID, roomUUID: player.RoomUUID}
+	return true
+}
+
+// Replace adds the player, handing back whoever was logged in as the same
+// character before them.
+func (r *Registry) Replace(player *players.Player) (*players.Player, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, ok := r.entries[player.UUID]
+	r.entries[player.UUID] = &entry{player: player, areaUUID: player.AreaUUID, roomUUID: player.RoomUUID}
+	if !ok {
+		return nil, false
+	}
+	return previous.player, true
+}
+
+func (r *Registry) Remove(playerUUID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, playerUUID)
+}
+
+// RemoveIfCurrent removes the player, as long as it is that player and not a
+// newer login of the same character.
+func (r *Registry) RemoveIfCurrent(player *players.Player) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[player.UUID]
+	if !ok || e.player != player {
+		return false
+	}
+	delete(r.entries, player.UUID)
+	return true
+}
+
+func (r *Registry) Get(playerUUID string) (*players.Player, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.entries[playerUUID]
+	if !ok {
+		return nil, false
+	}
+	return e.player, true
+}
+
+// GetByName finds a logged in player by name, ignoring case.
+func (r *Registry) GetByName(name string) (*players.Player, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.entries {
+		if strings.EqualFold(e.player.Name, name) {
+			return e.player, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) Contains(playerUUID string) bool {
+	_, ok := r.Get(playerUUID)
+	return ok
+}
+
+// Move updates where the player is.  Players who aren't logged in are ignored.
+func (r *Registry) Move(playerUUID string, areaUUID string, roomUUID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.entries[playerUUID]; ok {
+		e.areaUUID = areaUUID
+		e.roomUUID = roomUUID
+	}
+}
+
+func (r *Registry) Location(playerUUID string) (areaUUID string, roomUUID string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.entries[playerUUID]
+	if !ok {
+		return "", "", false
+	}
+	return e.areaUUID, e.roomUUID, true
+}
+
+func (r *Registry) InRoom(roomUUID string) []*players.Player {
+	return r.filter(func(e *entry) bool { return e.roomUUID == roomUUID })
+}
+
+func (r *Registry) InArea(areaUUID string) []*players.Player {
+	return r.filter(func(e *entry) bool { return e.areaUUID == areaUUID })
+}
+
+// All returns a snapshot of everyone logged in, it is safe to keep using after
+// players come and go.
+func (r *Registry) All() []*players.Player {
+	return r.filter(func(e *entry) bool { return true })
+}
+
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.entries)
+}
+
+func (r *Registry) filter(keep func(e *entry) bool) []*players.Player {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := make([]*players.Player, 0, len(r.entries))
+	for _, e := range r.entries {
+		if keep(e) {
+			matches = append(matches, e.player)
+		}
+	}
+	return matches
+}
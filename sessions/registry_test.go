@@ -0,0 +1,133 @@
+package sessions_test
+
+import (
+	"bytes"
+	"fmt"
+	"mud/notifications"
+	"mud/players"
+	"mud/sessions"
+	"mud/transport"
+	"sync"
+	"testing"
+)
+
+// fakeSession only needs to be written to, everything else on transport.Conn
+// is left nil.
+type fakeSession struct {
+	transport.Conn
+	mu  sync.Mutex
+	out bytes.Buffer
+}
+
+func (s *fakeSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.Write(p)
+}
+
+func newTestPlayer(idx int, roomUUID string) *players.Player {
+	player := players.NewPlayer(&fakeSession{})
+	player.UUID = fmt.Sprintf("player-%d", idx)
+	player.Name = fmt.Sprintf("Player%d", idx)
+	player.AreaUUID = "area"
+	player.RoomUUID = roomUUID
+	return player
+}
+
+func TestRegistry(t *testing.T) {
+	registry := sessions.NewRegistry()
+	first := newTestPlayer(1, "room-a")
+	second := newTestPlayer(2, "room-b")
+	registry.Add(first)
+	registry.Add(second)
+
+	if registry.Len() != 2 {
+		t.Fatalf("expected 2 players, got %d", registry.Len())
+	}
+	if got := registry.InRoom("room-a"); len(got) != 1 || got[0] != first {
+		t.Errorf("expected only the first player in room-a, got %v", got)
+	}
+
+	registry.Move(second.UUID, "area", "room-a")
+	if got := registry.InRoom("room-a"); len(got) != 2 {
+		t.Errorf("expected both players in room-a after the move, got %d", len(got))
+	}
+	if got := registry.InArea("area"); len(got) != 2 {
+		t.Errorf("expected both players in the area, got %d", len(got))
+	}
+
+	registry.Remove(first.UUID)
+	if registry.Contains(first.UUID) {
+		t.Errorf("expected the first player to be removed")
+	}
+	if _, _, ok := registry.Location(first.UUID); ok {
+		t.Errorf("expected no location for a removed player")
+	}
+	registry.Move(first.UUID, "area", "room-b")
+	if registry.Contains(first.UUID) {
+		t.Errorf("expected moving a removed player not to add them back")
+	}
+}
+
+func TestRegistryDuplicateLogins(t *testing.T) {
+	registry := sessions.NewRegistry()
+	first := newTestPlayer(1, "room-a")
+	again := newTestPlayer(1, "room-b")
+
+	if !registry.AddIfAbsent(first) {
+		t.Fatalf("expected the first login to be added")
+	}
+	if registry.AddIfAbsent(again) {
+		t.Errorf("expected the second login of the same character to be refused")
+	}
+
+	previous, ok := registry.Replace(again)
+	if !ok || previous != first {
+		t.Fatalf("expected the second login to take over from the first, got %v", previous)
+	}
+	if _, roomUUID, _ := registry.Location(again.UUID); roomUUID != "room-b" {
+		t.Errorf("expected the new login's location, got %s", roomUUID)
+	}
+
+	// the old connection going away shouldn't log out the new one
+	if registry.RemoveIfCurrent(first) {
+		t.Errorf("expected the replaced login not to be removed")
+	}
+	if player, _ := registry.Get(again.UUID); player != again {
+		t.Errorf("expected the new login to still be registered")
+	}
+	if !registry.RemoveIfCurrent(again) || registry.Len() != 0 {
+		t.Errorf("expected the new login to be removed")
+	}
+}
+
+// Run with -race: lots of players logging in, walking around, getting
+// broadcast to and logging out all at once.
+func TestRegistryConcurrentAccess(t *testing.T) {
+	registry := sessions.NewRegistry()
+	notifier := notifications.NewNotifier(registry)
+	rooms := []string{"room-a", "room-b", "room-c"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			player := newTestPlayer(idx, rooms[idx%len(rooms)])
+			registry.Add(player)
+			for step := 0; step < 20; step++ {
+				registry.Move(player.UUID, "area", rooms[(idx+step)%len(rooms)])
+				notifier.NotifyRoom(rooms[step%len(rooms)], player.UUID, "hello\n")
+				notifier.NotifyPlayer(player.UUID, "hi\n")
+				registry.InArea("area")
+			}
+			notifier.NotifyAll("goodbye\n")
+			registry.Remove(player.UUID)
+		}(i)
+	}
+	wg.Wait()
+
+	if registry.Len() != 0 {
+		t.Errorf("expected everyone to have logged out, %d left", registry.Len())
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"mud/display"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/jmoiron/sqlx"
+)
+
+// shutdown stops the game without losing anything: no more connections are
+// let in, every area loop is stopped so that nothing changes the players any
+// more, every player is saved and logged out and the database is closed.
+// Rebooting does the same and then starts the server again, telnet players are
+// kept connected through it (a copyover), everybody else has to reconnect.  It
+// only comes back when the server couldn't be started again.
+func (s *Server) shutdown(db *sqlx.DB, sshServer *ssh.Server, stopListening context.CancelFunc, stopAreas context.CancelFunc, reboot bool) error {
+	stopListening()
+	stopAreas()
+	s.areaLoops.Wait()
+
+	var kept []copyoverSession
+	for _, player := range s.connections.All() {
+		if err := player.Save(db); err != nil {
+			fmt.Printf("error saving %s: %v\n", player.Name, err)
+		}
+
+		if !reboot {
+			display.PrintWithColor(player, "\nThe server is shutting down, see you soon!\n", "danger")
+		} else if session, err := prepareCopyover(player); err == nil {
+			display.PrintWithColor(player, "\nThe server is rebooting, hold on a moment...\n", "danger")
+			kept = append(kept, session)
+			continue
+		} else {
+			display.PrintWithColor(player, "\nThe server is rebooting, come back in a moment!\n", "danger")
+		}
+		s.release(db, player)
+	}
+
+	if err := sshServer.Close(); err != nil {
+		fmt.Printf("error closing the ssh server: %v\n", err)
+	}
+
+	if reboot {
+		if err := writeCopyoverState(kept); err != nil {
+			fmt.Printf("error saving the copyover, everyone will have to reconnect: %v\n", err)
+			for _, session := range kept {
+				s.release(db, session.player)
+				session.file.Close()
+			}
+		}
+	}
+	if err := db.Close(); err != nil {
+		fmt.Printf("error closing the database: %v\n", err)
+	}
+	if reboot {
+		return restart()
+	}
+	return nil
+}
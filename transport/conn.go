@@ -0,0 +1,18 @@
+package transport
+
+import (
+	"io"
+	"net"
+)
+
+// Conn is a player's connection to the game, whichever protocol they came in
+// on.
+type Conn interface {
+	io.ReadWriteCloser
+	RemoteAddr() net.Addr
+	// SetEcho turns echoing of what the player types on and off, ie for
+	// password entry.
+	SetEcho(on bool)
+	// ReadLine reads up to the end of the line, without the line ending.
+	ReadLine() (string, error)
+}
@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// File hands over a copy of the connection's socket, so that it can be kept
+// open across a copyover.  Only tcp connections can be.
+func (c *TelnetConn) File() (*os.File, error) {
+	fileConn, ok := c.conn.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("%T connections can't be kept open", c.conn)
+	}
+	return fileConn.File()
+}
+
+// ResumeTelnetConn picks a connection kept open across a copyover back up.
+// The client already agreed to GMCP or not, so it isn't offered again.
+func ResumeTelnetConn(file *os.File, gmcpEnabled bool) (*TelnetConn, error) {
+	conn, err := net.FileConn(file)
+	if err != nil {
+		return nil, err
+	}
+	c := NewTelnetConn(conn)
+	c.gmcpEnabled.Store(gmcpEnabled)
+	return c, nil
+}
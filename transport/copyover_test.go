@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"bufio"
+	"net"
+	"testing"
+)
+
+func TestTelnetConnSurvivesCopyover(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	defer client.Close()
+	server, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("error accepting: %v", err)
+	}
+
+	original := NewTelnetConn(server)
+	original.gmcpEnabled.Store(true)
+	file, err := original.File()
+	if err != nil {
+		t.Fatalf("error getting the socket: %v", err)
+	}
+	// the old process's connection goes away, the copy of the socket doesn't
+	original.Close()
+
+	resumed, err := ResumeTelnetConn(file, original.GMCPEnabled())
+	file.Close()
+	if err != nil {
+		t.Fatalf("error resuming the connection: %v", err)
+	}
+	defer resumed.Close()
+
+	if !resumed.GMCPEnabled() {
+		t.Errorf("expected gmcp to still be enabled")
+	}
+
+	resumed.Write([]byte("still here\n"))
+	line, err := bufio.NewReader(client).ReadString('\n')
+	if err != nil {
+		t.Fatalf("error reading from the resumed connection: %v", err)
+	}
+	if line != "still here\r\n" {
+		t.Errorf("expected %q, got %q", "still here\r\n", line)
+	}
+}
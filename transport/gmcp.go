@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// GMCP, the Generic MUD Communication Protocol, rides along inside telnet
+// subnegotiation as "Package.Name <json>".
+const optGMCP byte = 201
+
+// GMCPSender is implemented by connections which can send structured data to
+// the client alongside the text, for health bars and maps and the like.
+type GMCPSender interface {
+	SendGMCP(pkg string, data interface{}) error
+}
+
+// OfferGMCP tells the client we speak GMCP.  Nothing is sent until the client
+// agrees.
+func (c *TelnetConn) OfferGMCP() error {
+	return c.writeRaw([]byte{iac, will, optGMCP})
+}
+
+func (c *TelnetConn) GMCPEnabled() bool {
+	return c.gmcpEnabled.Load()
+}
+
+func (c *TelnetConn) SendGMCP(pkg string, data interface{}) error {
+	if !c.GMCPEnabled() {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding gmcp %s: %v", pkg, err)
+	}
+
+	message := append([]byte(pkg+" "), payload...)
+	message = bytes.ReplaceAll(message, []byte{iac}, []byte{iac, iac})
+
+	out := append([]byte{iac, sb, optGMCP}, message...)
+	out = append(out, iac, se)
+	return c.writeRaw(out)
+}
@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// IdleTimeout is how long a connection can go without the player typing
+// anything before it is closed.  Zero leaves idle connections open.
+var IdleTimeout time.Duration
+
+// IdleTracked is implemented by connections with an idle timeout.  Touch
+// counts as the player doing something, for input which doesn't get read
+// through the connection, ie the Bubble Tea program's.
+type IdleTracked interface {
+	Touch()
+}
+
+// idleTimer closes a connection once nothing has been read from it for
+// IdleTimeout.  The zero value does nothing, so connections made while the
+// timeout is off don't need to check.
+type idleTimer struct {
+	mu      sync.Mutex
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (t *idleTimer) start(timeout time.Duration, conn io.WriteCloser) {
+	if timeout <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timeout = timeout
+	t.timer = time.AfterFunc(timeout, func() {
+		fmt.Fprintf(conn, "\nYou have been idle for %s, goodbye.\n", timeout)
+		conn.Close()
+	})
+}
+
+func (t *idleTimer) touch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer != nil {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+func (t *idleTimer) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
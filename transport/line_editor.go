@@ -0,0 +1,325 @@
+package transport
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHistory is how many lines a History hangs on to.
+const maxHistory = 100
+
+// History is what a player has typed this session, for going back through
+// with the arrow keys and running again with !! and !<prefix>.
+type History struct {
+	entries []string
+	// where the arrow keys have got to, len(entries) when they aren't in use
+	position int
+	// what was being typed before going back through the history
+	draft string
+}
+
+// Add remembers the line, unless it's empty or the same as the last one.
+func (h *History) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line != "" && (len(h.entries) == 0 || h.entries[len(h.entries)-1] != line) {
+		h.entries = append(h.entries, line)
+		if len(h.entries) > maxHistory {
+			h.entries = h.entries[len(h.entries)-maxHistory:]
+		}
+	}
+	h.position = len(h.entries)
+	h.draft = ""
+}
+
+// Previous goes back a line, current is what has been typed so far so that
+// Next can get back to it.
+func (h *History) Previous(current string) (string, bool) {
+	if h.position == 0 {
+		return "", false
+	}
+	if h.position == len(h.entries) {
+		h.draft = current
+	}
+	h.position--
+	return h.entries[h.position], true
+}
+
+// Next goes forward a line, ending up back at what was being typed.
+func (h *History) Next() (string, bool) {
+	if h.position >= len(h.entries) {
+		return "", false
+	}
+	h.position++
+	if h.position == len(h.entries) {
+		return h.draft, true
+	}
+	return h.entries[h.position], true
+}
+
+// Recall turns !! into the last line, and !<prefix> into the last line
+// starting with it.  Anything else is left as it is.
+func (h *History) Recall(line string) (string, error) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "!") || len(line) == 1 {
+		return line, nil
+	}
+
+	if strings.HasPrefix(line, "!!") {
+		if len(h.entries) == 0 {
+			return "", fmt.Errorf("there's nothing to repeat")
+		}
+		return h.entries[len(h.entries)-1] + line[2:], nil
+	}
+
+	prefix := strings.ToLower(line[1:])
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if strings.HasPrefix(strings.ToLower(h.entries[i]), prefix) {
+			return h.entries[i], nil
+		}
+	}
+	return "", fmt.Errorf("you haven't typed anything starting with %s", line[1:])
+}
+
+// Completer suggests the words the last word of the line could be.
+type Completer func(line string) []string
+
+// CompleteLine fills in as much of the last word of the line as the
+// suggestions agree on.  When more than one could fit they are returned, so
+// they can be shown.
+func CompleteLine(line string, complete Completer) (string, []string) {
+	start := strings.LastIndexAny(line, " \t") + 1
+	word := strings.ToLower(line[start:])
+
+	var matches []string
+	seen := map[string]bool{}
+	for _, suggestion := range complete(line) {
+		if strings.HasPrefix(strings.ToLower(suggestion), word) && !seen[suggestion] {
+			seen[suggestion] = true
+			matches = append(matches, suggestion)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return line, nil
+	case 1:
+		return line[:start] + matches[0] + " ", nil
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(strings.ToLower(match), strings.ToLower(common)) {
+			_, size := utf8.DecodeLastRuneInString(common)
+			common = common[:len(common)-size]
+		}
+	}
+	if len(common) > len(word) {
+		return line[:start] + common, matches
+	}
+	return line, matches
+}
+
+// keys the line editor understands, escape sequences included
+const (
+	keyCtrlA     = 0x01
+	keyCtrlB     = 0x02
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyCtrlF     = 0x06
+	keyBackspace = 0x08
+	keyCtrlK     = 0x0b
+	keyCtrlU     = 0x15
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// lineEditor reads a line from a terminal which has left the editing to us,
+// which is what ssh clients with a pty do.  The cursor can be moved with the
+// arrow keys, home and end, and the emacs keys, and characters deleted on
+// either side of it.
+type lineEditor struct {
+	line   []rune
+	cursor int
+	// what is typed is shown as *s, ie for passwords
+	mask bool
+	// the \n of a \r\n has to be skipped rather than ending another line
+	skipLF bool
+}
+
+// readLine edits a line until enter is pressed, echoing it to out as it goes.
+func (e *lineEditor) readLine(readByte func() (byte, error), out io.Writer) (string, error) {
+	e.line = e.line[:0]
+	e.cursor = 0
+
+	for {
+		b, err := readByte()
+		if err != nil {
+			return "", err
+		}
+		if b == '\n' && e.skipLF {
+			e.skipLF = false
+			continue
+		}
+		e.skipLF = b == '\r'
+
+		switch b {
+		case '\r', '\n':
+			fmt.Fprintln(out)
+			return string(e.line), nil
+		case keyBackspace, keyDelete:
+			e.deleteBackward(out)
+		case keyCtrlD:
+			e.deleteForward(out)
+		case keyCtrlA:
+			e.moveTo(out, 0)
+		case keyCtrlE:
+			e.moveTo(out, len(e.line))
+		case keyCtrlB:
+			e.moveTo(out, e.cursor-1)
+		case keyCtrlF:
+			e.moveTo(out, e.cursor+1)
+		case keyCtrlU:
+			e.replace(out, e.line[e.cursor:], 0)
+		case keyCtrlK:
+			e.replace(out, e.line[:e.cursor], e.cursor)
+		case keyEscape:
+			err = e.escapeSequence(readByte, out)
+			if err != nil {
+				return "", err
+			}
+		default:
+			if b < 0x20 {
+				// tab and the other control keys don't do anything here
+				continue
+			}
+			r, err := readRune(b, readByte)
+			if err != nil {
+				return "", err
+			}
+			e.insert(out, r)
+		}
+	}
+}
+
+// readRune reads the rest of the character which starts with b.
+func readRune(b byte, readByte func() (byte, error)) (rune, error) {
+	if b < utf8.RuneSelf {
+		return rune(b), nil
+	}
+	encoded := []byte{b}
+	for !utf8.FullRune(encoded) {
+		next, err := readByte()
+		if err != nil {
+			return 0, err
+		}
+		encoded = append(encoded, next)
+	}
+	r, _ := utf8.DecodeRune(encoded)
+	return r, nil
+}
+
+// escapeSequence handles the keys which send ESC [ or ESC O and a code.
+// Anything it doesn't know is read to the end and ignored.
+func (e *lineEditor) escapeSequence(readByte func() (byte, error), out io.Writer) error {
+	introducer, err := readByte()
+	if err != nil {
+		return err
+	}
+	if introducer != '[' && introducer != 'O' {
+		return nil
+	}
+
+	var parameters []byte
+	for {
+		b, err := readByte()
+		if err != nil {
+			return err
+		}
+		if b < 0x40 || b > 0x7e {
+			parameters = append(parameters, b)
+			continue
+		}
+
+		switch {
+		case b == 'C':
+			e.moveTo(out, e.cursor+1)
+		case b == 'D':
+			e.moveTo(out, e.cursor-1)
+		case b == 'H', b == '~' && (string(parameters) == "1" || string(parameters) == "7"):
+			e.moveTo(out, 0)
+		case b == 'F', b == '~' && (string(parameters) == "4" || string(parameters) == "8"):
+			e.moveTo(out, len(e.line))
+		case b == '~' && string(parameters) == "3":
+			e.deleteForward(out)
+		}
+		return nil
+	}
+}
+
+func (e *lineEditor) insert(out io.Writer, r rune) {
+	if e.cursor == len(e.line) {
+		e.line = append(e.line, r)
+		e.cursor++
+		fmt.Fprint(out, e.shown(e.line[e.cursor-1:]))
+		return
+	}
+	line := append(append(append([]rune{}, e.line[:e.cursor]...), r), e.line[e.cursor:]...)
+	e.replace(out, line, e.cursor+1)
+}
+
+func (e *lineEditor) deleteBackward(out io.Writer) {
+	if e.cursor == 0 {
+		return
+	}
+	line := append(append([]rune{}, e.line[:e.cursor-1]...), e.line[e.cursor:]...)
+	e.replace(out, line, e.cursor-1)
+}
+
+func (e *lineEditor) deleteForward(out io.Writer) {
+	if e.cursor == len(e.line) {
+		return
+	}
+	line := append(append([]rune{}, e.line[:e.cursor]...), e.line[e.cursor+1:]...)
+	e.replace(out, line, e.cursor)
+}
+
+// replace redraws the line from where it starts on the screen, then puts the
+// cursor where it should be.
+func (e *lineEditor) replace(out io.Writer, line []rune, cursor int) {
+	var redraw strings.Builder
+	if e.cursor > 0 {
+		fmt.Fprintf(&redraw, "\x1b[%dD", e.cursor)
+	}
+	redraw.WriteString(e.shown(line))
+	redraw.WriteString("\x1b[K")
+	if back := len(line) - cursor; back > 0 {
+		fmt.Fprintf(&redraw, "\x1b[%dD", back)
+	}
+	fmt.Fprint(out, redraw.String())
+
+	e.line = append(e.line[:0], line...)
+	e.cursor = cursor
+}
+
+func (e *lineEditor) moveTo(out io.Writer, cursor int) {
+	if cursor < 0 || cursor > len(e.line) || cursor == e.cursor {
+		return
+	}
+	if cursor < e.cursor {
+		fmt.Fprintf(out, "\x1b[%dD", e.cursor-cursor)
+	} else {
+		fmt.Fprintf(out, "\x1b[%dC", cursor-e.cursor)
+	}
+	e.cursor = cursor
+}
+
+func (e *lineEditor) shown(runes []rune) string {
+	if e.mask {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes)
+}
@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readEdited(t *testing.T, editor *lineEditor, typed string) (string, string) {
+	t.Helper()
+	input := strings.NewReader(typed)
+	var echoed bytes.Buffer
+	line, err := editor.readLine(input.ReadByte, &echoed)
+	if err != nil {
+		t.Fatalf("readLine(%q) returned %v", typed, err)
+	}
+	return line, echoed.String()
+}
+
+func TestLineEditorEditing(t *testing.T) {
+	tests := map[string]string{
+		"look\r":                               "look",
+		"lok\x1b[D\x1b[Do\r":                   "look",
+		"lookk\x7f\r":                          "look",
+		"xlook\x01\x1b[3~\r":                   "look",
+		"ook\x1bOHl\x1bOF!\r":                  "look!",
+		"say hi\x15look\r":                     "look",
+		"look north\x01\x06\x06\x06\x06\x0b\r": "look",
+		"say caf\xc3\xa9\x1b[D\b\r":            "say caé",
+		"\x1b[A\x1b[1;5Clook\t\r":              "look",
+	}
+	for typed, expected := range tests {
+		if line, _ := readEdited(t, &lineEditor{}, typed); line != expected {
+			t.Errorf("typing %q gave %q, expected %q", typed, line, expected)
+		}
+	}
+}
+
+func TestLineEditorEcho(t *testing.T) {
+	_, echoed := readEdited(t, &lineEditor{}, "lok\x1b[Do\r")
+	if expected := "lok\x1b[1D\x1b[2Dlook\x1b[K\x1b[1D\n"; echoed != expected {
+		t.Errorf("expected the line to be redrawn as %q, got %q", expected, echoed)
+	}
+
+	_, echoed = readEdited(t, &lineEditor{mask: true}, "secret\x7f\r")
+	if strings.Contains(echoed, "secret") || !strings.Contains(echoed, "*****") {
+		t.Errorf("expected a masked line to only show *s, got %q", echoed)
+	}
+}
+
+func TestLineEditorLineEndings(t *testing.T) {
+	input := strings.NewReader("north\r\nsouth\n")
+	editor := &lineEditor{}
+	for _, expected := range []string{"north", "south"} {
+		line, err := editor.readLine(input.ReadByte, io.Discard)
+		if err != nil || line != expected {
+			t.Errorf("expected %q, got %q, %v", expected, line, err)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	var history History
+	for _, line := range []string{"look", "kill goblin", "kill goblin", "", "say hi"} {
+		history.Add(line)
+	}
+
+	var back []string
+	for line, ok := history.Previous("ki"); ok; line, ok = history.Previous("") {
+		back = append(back, line)
+	}
+	if strings.Join(back, "|") != "say hi|kill goblin|look" {
+		t.Errorf("expected to go back through each line once, got %q", back)
+	}
+	history.Next()
+	history.Next()
+	if line, _ := history.Next(); line != "ki" {
+		t.Errorf("expected to end up back at what was being typed, got %q", line)
+	}
+
+	recalls := map[string]string{
+		"!!":       "say hi",
+		"!! there": "say hi there",
+		"!KI":      "kill goblin",
+		"!":        "!",
+		"look":     "look",
+	}
+	for typed, expected := range recalls {
+		if got, err := history.Recall(typed); err != nil || got != expected {
+			t.Errorf("Recall(%q) = %q, %v, expected %q", typed, got, err, expected)
+		}
+	}
+	if _, err := history.Recall("!flee"); err == nil {
+		t.Errorf("expected recalling something never typed to fail")
+	}
+	if _, err := (&History{}).Recall("!!"); err == nil {
+		t.Errorf("expected !! with nothing typed yet to fail")
+	}
+}
+
+func TestCompleteLine(t *testing.T) {
+	complete := func(line string) []string {
+		return []string{"look", "logout", "kill", "goblin", "gold"}
+	}
+	tests := []struct {
+		line     string
+		expected string
+		options  int
+	}{
+		{"ki", "kill ", 0},
+		{"lo", "lo", 2},
+		{"kill gob", "kill goblin ", 0},
+		{"kill go", "kill go", 2},
+		{"kill x", "kill x", 0},
+	}
+	for _, test := range tests {
+		line, options := CompleteLine(test.line, complete)
+		if line != test.expected || len(options) != test.options {
+			t.Errorf("CompleteLine(%q) = %q, %q", test.line, line, options)
+		}
+	}
+
+	if line, _ := CompleteLine("l", func(string) []string { return []string{"logout", "look"} }); line != "lo" {
+		t.Errorf("expected what the options have in common to be filled in, got %q", line)
+	}
+}
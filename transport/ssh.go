@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"github.com/charmbracelet/ssh"
+)
+
+// SSHConn wraps an ssh session.  ssh clients in a pty don't echo what is typed,
+// or let it be edited, so it's up to us.
+type SSHConn struct {
+	ssh.Session
+	echoOff bool
+	idle    idleTimer
+	editor  lineEditor
+}
+
+func NewSSHConn(session ssh.Session) *SSHConn {
+	c := &SSHConn{Session: session}
+	c.idle.start(IdleTimeout, c)
+	return c
+}
+
+// SetEcho off masks what is typed with *s.
+func (c *SSHConn) SetEcho(on bool) {
+	c.echoOff = !on
+}
+
+func (c *SSHConn) Read(p []byte) (int, error) {
+	n, err := c.Session.Read(p)
+	if n > 0 {
+		c.idle.touch()
+	}
+	return n, err
+}
+
+func (c *SSHConn) Touch() {
+	c.idle.touch()
+}
+
+func (c *SSHConn) Close() error {
+	c.idle.stop()
+	return c.Session.Close()
+}
+
+// ReadLine lets the player fix what they type before sending it, see
+// lineEditor.
+func (c *SSHConn) ReadLine() (string, error) {
+	c.editor.mask = c.echoOff
+	return c.editor.readLine(c.readByte, c.Session)
+}
+
+func (c *SSHConn) readByte() (byte, error) {
+	b := make([]byte, 1)
+	_, err := c.Read(b)
+	return b[0], err
+}
+
+// KeyFingerprintExtension is where the ssh server puts the fingerprint of the
+// key the player proved they have, in the connection's permissions.
+const KeyFingerprintExtension = "mud-key-fingerprint"
+
+// KeyAuthenticated is implemented by connections which authenticated with a
+// public key.
+type KeyAuthenticated interface {
+	// KeyFingerprint is the fingerprint of the key, or empty when the player
+	// didn't use one.
+	KeyFingerprint() string
+}
+
+func (c *SSHConn) KeyFingerprint() string {
+	return c.Permissions().Extensions[KeyFingerprintExtension]
+}
@@ -0,0 +1,260 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// telnet commands and options, see RFC 854 and RFC 857
+const (
+	iac  byte = 255
+	dont byte = 254
+	do   byte = 253
+	wont byte = 252
+	will byte = 251
+	sb   byte = 250
+	se   byte = 240
+
+	optEcho byte = 1
+)
+
+// TelnetConn speaks just enough telnet for MUD clients: negotiation is
+// stripped out of what the player types, line endings are translated, and
+// echo can be turned off for passwords.
+type TelnetConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+	echoOff bool
+	idle    idleTimer
+	// set once the client agrees to GMCP
+	gmcpEnabled atomic.Bool
+}
+
+func NewTelnetConn(conn net.Conn) *TelnetConn {
+	c := &TelnetConn{conn: conn, reader: bufio.NewReader(conn)}
+	c.idle.start(IdleTimeout, c)
+	return c
+}
+
+func (c *TelnetConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *TelnetConn) Touch() {
+	c.idle.touch()
+}
+
+func (c *TelnetConn) Close() error {
+	c.idle.stop()
+	return c.conn.Close()
+}
+
+// Write translates line endings to CRLF and escapes any IAC bytes.
+func (c *TelnetConn) Write(p []byte) (int, error) {
+	out := bytes.ReplaceAll(p, []byte{iac}, []byte{iac, iac})
+	out = bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n"))
+	out = bytes.ReplaceAll(out, []byte("\n"), []byte("\r\n"))
+	if err := c.writeRaw(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *TelnetConn) writeRaw(p []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err := c.conn.Write(p)
+	return err
+}
+
+// SetEcho off tells the client that we will do the echoing, and then we don't,
+// which is how telnet hides passwords.
+func (c *TelnetConn) SetEcho(on bool) {
+	c.echoOff = !on
+	command := will
+	if on {
+		command = wont
+	}
+	if err := c.writeRaw([]byte{iac, command, optEcho}); err != nil {
+		log.Printf("error negotiating echo: %v", err)
+	}
+}
+
+// Read hands back what the player typed, with the telnet negotiation removed.
+func (c *TelnetConn) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := 0
+	for n < len(p) {
+		b, err := c.readByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		p[n] = b
+		n++
+		if c.reader.Buffered() == 0 {
+			break
+		}
+	}
+	return n, nil
+}
+
+func (c *TelnetConn) ReadLine() (string, error) {
+	var line []byte
+	for {
+		b, err := c.readByte()
+		if err != nil {
+			return "", err
+		}
+		switch b {
+		case '\n':
+			if c.echoOff {
+				// the client isn't echoing the line ending either
+				c.Write([]byte("\n"))
+			}
+			return string(line), nil
+		case '\r', 0:
+			// clients end lines with CR LF or CR NUL
+			continue
+		default:
+			line = append(line, b)
+		}
+	}
+}
+
+// readByte returns the next byte of data, handling any telnet commands in the
+// way.
+func (c *TelnetConn) readByte() (byte, error) {
+	for {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != iac {
+			c.idle.touch()
+			return b, nil
+		}
+
+		command, err := c.reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch command {
+		case iac:
+			return iac, nil
+		case will, wont, do, dont:
+			option, err := c.reader.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			c.handleNegotiation(command, option)
+		case sb:
+			data, err := c.readSubnegotiation()
+			if err != nil {
+				return 0, err
+			}
+			if len(data) > 0 {
+				c.handleSubnegotiation(data[0], data[1:])
+			}
+		default:
+			// NOP, GA, AYT and friends, nothing to do
+		}
+	}
+}
+
+func (c *TelnetConn) readSubnegotiation() ([]byte, error) {
+	var data []byte
+	for {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != iac {
+			data = append(data, b)
+			continue
+		}
+		next, err := c.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if next == se {
+			return data, nil
+		}
+		data = append(data, next)
+	}
+}
+
+func (c *TelnetConn) handleNegotiation(command byte, option byte) {
+	switch option {
+	case optGMCP:
+		switch command {
+		case do:
+			c.gmcpEnabled.Store(true)
+		case dont:
+			c.gmcpEnabled.Store(false)
+		}
+		return
+	case optEcho:
+		return
+	}
+
+	// refuse anything the client asks for that we don't know about.
+	switch command {
+	case do:
+		c.writeRaw([]byte{iac, wont, option})
+	case will:
+		c.writeRaw([]byte{iac, dont, option})
+	}
+}
+
+// handleSubnegotiation is where the client tells us things, ie the GMCP
+// Core.Hello and Core.Supports.Set packages.  We send the same packages to
+// everyone for now, so there is nothing to do with them.
+func (c *TelnetConn) handleSubnegotiation(option byte, data []byte) {
+}
+
+// ListenTelnet accepts telnet connections on the address and hands each one to
+// handler on its own goroutine.
+// ListenTelnet accepts telnet connections until the context is done.
+func ListenTelnet(ctx context.Context, address string, handler func(conn Conn)) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			telnetConn := NewTelnetConn(conn)
+			defer telnetConn.Close()
+			if err := telnetConn.OfferGMCP(); err != nil {
+				log.Printf("error offering gmcp: %v", err)
+				return
+			}
+			handler(telnetConn)
+		}()
+	}
+}
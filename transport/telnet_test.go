@@ -0,0 +1,135 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTelnetReadLineStripsNegotiation(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := NewTelnetConn(server)
+	defer conn.Close()
+
+	go func() {
+		// the client offers terminal type in the middle of a line, and
+		// sends a subnegotiation before the line ending
+		client.Write([]byte{'l', 'o', 'o', iac, will, 24, 'k'})
+		client.Write([]byte{iac, sb, 24, 0, 'x', 't', 'e', 'r', 'm', iac, se, '\r', '\n'})
+	}()
+
+	// we refuse the terminal type offer
+	refusal := make(chan []byte)
+	go func() {
+		buf := make([]byte, 3)
+		io.ReadFull(client, buf)
+		refusal <- buf
+	}()
+
+	line, err := conn.ReadLine()
+	if err != nil {
+		t.Fatalf("ReadLine returned error: %v", err)
+	}
+	if line != "look" {
+		t.Errorf("expected line %q, got %q", "look", line)
+	}
+	if got := <-refusal; !bytes.Equal(got, []byte{iac, dont, 24}) {
+		t.Errorf("expected the client's offer to be refused, got %v", got)
+	}
+}
+
+func TestTelnetWrite(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := NewTelnetConn(server)
+	defer conn.Close()
+
+	received := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		n, _ := client.Read(buf)
+		received <- buf[:n]
+	}()
+
+	conn.Write([]byte("hi\nthere\r\n\xff"))
+	expected := []byte("hi\r\nthere\r\n\xff\xff")
+	if got := <-received; !bytes.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestTelnetSetEcho(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := NewTelnetConn(server)
+	defer conn.Close()
+
+	received := make(chan []byte)
+	go func() {
+		buf := make([]byte, 3)
+		io.ReadFull(client, buf)
+		received <- buf
+	}()
+
+	conn.SetEcho(false)
+	if got := <-received; !bytes.Equal(got, []byte{iac, will, optEcho}) {
+		t.Errorf("expected IAC WILL ECHO, got %v", got)
+	}
+}
+
+func TestTelnetGMCP(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := NewTelnetConn(server)
+	defer conn.Close()
+
+	// nothing goes out before the client agrees to gmcp
+	if err := conn.SendGMCP("Char.Vitals", map[string]int{"hp": 10}); err != nil {
+		t.Fatalf("SendGMCP returned error: %v", err)
+	}
+
+	go client.Write([]byte{iac, do, optGMCP, 'x', '\n'})
+	if _, err := conn.ReadLine(); err != nil {
+		t.Fatalf("ReadLine returned error: %v", err)
+	}
+	if !conn.GMCPEnabled() {
+		t.Fatalf("expected gmcp to be enabled after IAC DO GMCP")
+	}
+
+	received := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		n, _ := client.Read(buf)
+		received <- buf[:n]
+	}()
+
+	if err := conn.SendGMCP("Char.Vitals", map[string]int{"hp": 10}); err != nil {
+		t.Fatalf("SendGMCP returned error: %v", err)
+	}
+	expected := append([]byte{iac, sb, optGMCP}, []byte(`Char.Vitals {"hp":10}`)...)
+	expected = append(expected, iac, se)
+	if got := <-received; !bytes.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestTelnetIdleTimeout(t *testing.T) {
+	IdleTimeout = 50 * time.Millisecond
+	defer func() { IdleTimeout = 0 }()
+
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := NewTelnetConn(server)
+	defer conn.Close()
+
+	goodbye, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatalf("error reading from the idle connection: %v", err)
+	}
+	if !bytes.Contains(goodbye, []byte("idle")) {
+		t.Errorf("expected to be told why we were disconnected, got %q", goodbye)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"mud/areas"
+	"mud/notifications"
+	"mud/players"
+	"mud/transport"
+	"mud/world_state"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/jmoiron/sqlx"
+)
+
+// how many lines of output we hang on to for scrolling back through
+const maxScrollback = 1000
+
+// everything written to the player's session ends up as one of these
+type outputMsg string
+
+// the status bar shows the prompt, rather than it being printed after every
+// message and cluttering up the scrollback
+type promptMsg string
+
+type commandDoneMsg struct {
+	prompt string
+}
+
+// teaSession stands in for the player's ssh session once they are logged in.
+// Writes from display.PrintWithColor and the Notifier are sent to the Bubble
+// Tea program, which adds them to the scrollback, instead of straight down the
+// wire where they would trample the UI.
+type teaSession struct {
+	transport.Conn
+	program *tea.Program
+}
+
+func (s *teaSession) Write(p []byte) (int, error) {
+	s.program.Send(outputMsg(p))
+	return len(p), nil
+}
+
+func (s *teaSession) SetPrompt(prompt string) {
+	s.program.Send(promptMsg(prompt))
+}
+
+func (s *teaSession) KeyFingerprint() string {
+	if keyed, ok := s.Conn.(transport.KeyAuthenticated); ok {
+		return keyed.KeyFingerprint()
+	}
+	return ""
+}
+
+func (s *teaSession) Touch() {
+	if idle, ok := s.Conn.(transport.IdleTracked); ok {
+		idle.Touch()
+	}
+}
+
+func (s *teaSession) Close() error {
+	s.program.Quit()
+	return nil
+}
+
+type mudModel struct {
+	db           *sqlx.DB
+	areaChannels map[string]chan areas.Action
+	router       CommandRouterInterface
+	player       *players.Player
+	// the player's prompt, as it was last rendered
+	prompt string
+	// what the player has typed, and what tab fills in
+	history  *transport.History
+	complete transport.Completer
+
+	viewport    viewport.Model
+	input       textinput.Model
+	statusStyle lipgloss.Style
+	scrollback  []string
+	ready       bool
+	width       int
+	height      int
+
+	// commands are run off of the Update loop, one at a time
+	commandMu *sync.Mutex
+}
+
+func newMUDModel(db *sqlx.DB, router CommandRouterInterface, player *players.Player, areaChannels map[string]chan areas.Action, complete transport.Completer, renderer *lipgloss.Renderer) mudModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Focus()
+
+	return mudModel{
+		db:           db,
+		areaChannels: areaChannels,
+		router:       router,
+		player:       player,
+		prompt:       player.RenderPrompt(),
+		history:      &transport.History{},
+		complete:     complete,
+		input:        input,
+		statusStyle:  renderer.NewStyle().Reverse(true),
+		commandMu:    &sync.Mutex{},
+	}
+}
+
+func (m mudModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.runCommand("look"))
+}
+
+// runCommand routes the command on its own goroutine.  Handlers write to the
+// player's session, which sends messages back to the program, so running them
+// inside Update would deadlock.
+func (m mudModel) runCommand(command string) tea.Cmd {
+	return func() tea.Msg {
+		m.commandMu.Lock()
+		defer m.commandMu.Unlock()
+
+		m.router.HandleCommand(m.db, m.player, []byte(command), m.areaChannels[m.player.AreaUUID], func(string) {})
+		return commandDoneMsg{prompt: m.player.RenderPrompt()}
+	}
+}
+
+func (m mudModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		viewportHeight := msg.Height - 2
+		if viewportHeight < 1 {
+			viewportHeight = 1
+		}
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, viewportHeight)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = viewportHeight
+		}
+		m.input.Width = msg.Width - len(m.input.Prompt) - 1
+		m.refreshScrollback()
+
+	case outputMsg:
+		m.appendOutput(string(msg))
+
+	case promptMsg:
+		m.prompt = string(msg)
+
+	case commandDoneMsg:
+		m.prompt = msg.prompt
+
+	case tea.KeyMsg:
+		if idle, ok := m.player.GetSession().(transport.IdleTracked); ok {
+			idle.Touch()
+		}
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyCtrlD:
+			return m, m.runCommand("logout")
+		case tea.KeyEnter:
+			command, err := m.history.Recall(m.input.Value())
+			m.input.Reset()
+			if err != nil {
+				m.appendOutput(fmt.Sprintf("Nothing was run, %v.\n", err))
+				return m, nil
+			}
+			if command == "" {
+				return m, nil
+			}
+			m.history.Add(command)
+			m.appendOutput(fmt.Sprintf("> %s\n", command))
+			return m, m.runCommand(command)
+		case tea.KeyUp:
+			if line, ok := m.history.Previous(m.input.Value()); ok {
+				m.input.SetValue(line)
+				m.input.CursorEnd()
+			}
+			return m, nil
+		case tea.KeyDown:
+			if line, ok := m.history.Next(); ok {
+				m.input.SetValue(line)
+				m.input.CursorEnd()
+			}
+			return m, nil
+		case tea.KeyTab:
+			m.completeInput()
+			return m, nil
+		case tea.KeyPgUp, tea.KeyPgDown:
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+// completeInput fills in the word before the cursor, or lists what it could
+// be when that's all it can do.
+func (m *mudModel) completeInput() {
+	value := []rune(m.input.Value())
+	before, after := string(value[:m.input.Position()]), string(value[m.input.Position():])
+
+	completed, options := transport.CompleteLine(before, m.complete)
+	if completed == before {
+		if len(options) > 0 {
+			m.appendOutput(strings.Join(options, "  ") + "\n")
+		}
+		return
+	}
+	m.input.SetValue(completed + after)
+	m.input.SetCursor(len([]rune(completed)))
+}
+
+func (m *mudModel) appendOutput(output string) {
+	output = strings.ReplaceAll(output, "\r", "")
+	if output == "" {
+		return
+	}
+
+	// carry on the last line if it wasn't finished
+	lines := strings.Split(output, "\n")
+	if len(m.scrollback) > 0 {
+		m.scrollback[len(m.scrollback)-1] += lines[0]
+		lines = lines[1:]
+	}
+	m.scrollback = append(m.scrollback, lines...)
+	if len(m.scrollback) > maxScrollback {
+		m.scrollback = m.scrollback[len(m.scrollback)-maxScrollback:]
+	}
+	m.refreshScrollback()
+}
+
+func (m *mudModel) refreshScrollback() {
+	if !m.ready {
+		return
+	}
+	following := m.viewport.AtBottom()
+	m.viewport.SetContent(strings.Join(m.scrollback, "\n"))
+	if following {
+		m.viewport.GotoBottom()
+	}
+}
+
+func (m mudModel) statusBar() string {
+	status := fmt.Sprintf(" %s  %s", m.player.Name, strings.TrimSpace(m.prompt))
+	return m.statusStyle.Width(m.width).Render(status)
+}
+
+func (m mudModel) View() string {
+	if !m.ready {
+		return "Welcome to the MUD!"
+	}
+	return fmt.Sprintf("%s\n%s\n%s", m.viewport.View(), m.statusBar(), m.input.View())
+}
+
+// BubbleteaMUD logs the player in and runs the game in a Bubble Tea program.
+// Sessions without a terminal, ie piped input, get the plain line based loop.
+func BubbleteaMUD(db *sqlx.DB, server *Server, notifier *notifications.Notifier, areaChannels map[string]chan areas.Action, roomToAreaMap map[string]string, worldState *world_state.WorldState) wish.Middleware {
+	return func(sh ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			router := server.newRouter(notifier, worldState)
+
+			conn := transport.NewSSHConn(s)
+			pty, windowChanges, isPty := s.Pty()
+			if !isPty {
+				server.handleConnection(conn, router, db, areaChannels, roomToAreaMap, worldState)
+				sh(s)
+				return
+			}
+
+			player, err := players.LoginPlayer(conn, db)
+			if err != nil || player == nil {
+				fmt.Fprintf(s, "Login failed: %v\n", err)
+				return
+			}
+
+			complete := func(line string) []string {
+				return router.Complete(player, worldState.GetRoom(player.RoomUUID, false), line)
+			}
+			m := newMUDModel(db, router, player, areaChannels, complete, bm.MakeRenderer(s))
+			program := tea.NewProgram(m, append(bm.MakeOptions(s), tea.WithAltScreen())...)
+			// anyone can write to the player once they're admitted, which has to
+			// go through the program rather than underneath it
+			player.Session = &teaSession{Conn: conn, program: program}
+			if !server.admit(player, conn) {
+				return
+			}
+
+			currentRoom := worldState.GetRoom(player.RoomUUID, false)
+			currentRoom.AddPlayer(player)
+			notifyPlayersInRoomThatNewPlayerHasJoined(player, server.connections)
+
+			go func() {
+				program.Send(tea.WindowSizeMsg{Width: pty.Window.Width, Height: pty.Window.Height})
+				for {
+					select {
+					case <-s.Context().Done():
+						program.Quit()
+						return
+					case w := <-windowChanges:
+						program.Send(tea.WindowSizeMsg{Width: w.Width, Height: w.Height})
+					}
+				}
+			}()
+
+			if _, err := program.Run(); err != nil {
+				log.Println("Error running program:", err)
+			}
+			program.Kill()
+
+			server.release(db, player)
+			sh(s)
+		}
+	}
+}
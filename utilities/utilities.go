@@ -84,3 +84,23 @@ func CalculateAbilityModifier(val int32) int32 {
 		return 17
 	}
 }
+
+// MatchesName checks whether every word of the keyword starts one of the words
+// in the name, in order and ignoring case, so "corpse" and "cor reg" both
+// match "the corpse of Reg".
+func MatchesName(name string, keyword string) bool {
+	keywords := strings.Fields(strings.ToLower(keyword))
+	if len(keywords) == 0 {
+		return false
+	}
+	words := strings.Fields(strings.ToLower(name))
+	for _, word := range words {
+		if strings.HasPrefix(word, keywords[0]) {
+			keywords = keywords[1:]
+			if len(keywords) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"mud/migrations"
+	"mud/seed"
+)
+
+// runValidate checks what the server would check when starting up, without
+// starting it, so a config can be tried out before a reboot.  It also checks
+// the world files, printing file:line for anything wrong with them.
+func runValidate(args []string) error {
+	flags, configPath := newFlagSet("validate")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usage("validate")
+	}
+
+	cfg, db, err := openConfigured(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var problems []error
+	statuses, err := migrations.GetStatus(db)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			problems = append(problems, fmt.Errorf("migration %d %s hasn't been applied, run mud migrate up", status.Version, status.Name))
+		}
+	}
+
+	roomToAreaMap, err := loadRoomToAreaMap(db)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("error loading rooms: %v", err))
+	} else if err := checkStartRooms(cfg, roomToAreaMap); err != nil {
+		problems = append(problems, err)
+	}
+
+	// the world files, which are what a reboot or reseed would read
+	knownMobs, err := seed.KnownMobs(cfg.Database.MonsterImportsPath)
+	if err != nil {
+		fmt.Printf("Not checking mob slugs: %v\n", err)
+	}
+	worldErrors := 0
+	for _, diagnostic := range seed.ValidateWorld(cfg.World.StartRoomUUID, knownMobs) {
+		fmt.Println(diagnostic)
+		if diagnostic.Severity == seed.Error {
+			worldErrors++
+		}
+	}
+	if worldErrors > 0 {
+		problems = append(problems, fmt.Errorf("%d errors in the world files", worldErrors))
+	}
+
+	if len(problems) > 0 {
+		return errors.Join(problems...)
+	}
+	fmt.Println("Everything looks fine")
+	return nil
+}
@@ -36,11 +36,13 @@ func (worldState *WorldState) RemovePlayerFromRoom(roomUUID string, player *play
 }
 
 func (worldState *WorldState) AddPlayerToRoom(roomUUID string, player *players.Player) error {
-	areaUUID := worldState.RoomToAreaMap[roomUUID]
-	area := worldState.Areas[areaUUID]
-	room, err := area.GetRoomByUUID(roomUUID)
-	if err != nil {
-		return err
+	if _, ok := worldState.RoomToAreaMap[roomUUID]; !ok {
+		return fmt.Errorf("room UUID %s not found", roomUUID)
+	}
+	// the room is loaded if nobody has been there yet
+	room := worldState.GetRoom(roomUUID, false)
+	if room == nil {
+		return fmt.Errorf("room UUID %s could not be loaded", roomUUID)
 	}
 	room.AddPlayer(player)
 	player.RoomUUID = roomUUID